@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gordonklaus/portaudio"
+	"github.com/nomihq/camouflage/internal/virtdev"
+)
+
+// runListDevices implements `camouflage list`, printing every PortAudio
+// device so the right name for --input/--output is easy to find. It
+// supersedes ad-hoc device-probing scripts with a supported, cross-platform
+// command.
+func runListDevices() {
+	if err := portaudio.Initialize(); err != nil {
+		log.Fatalf("Failed to initialize PortAudio: %v", err)
+	}
+	defer func() {
+		if err := portaudio.Terminate(); err != nil {
+			log.Printf("Warning: Failed to terminate PortAudio: %v", err)
+		}
+	}()
+
+	devices, err := portaudio.Devices()
+	if err != nil {
+		log.Fatalf("Failed to enumerate devices: %v", err)
+	}
+
+	for _, d := range devices {
+		hostAPI := ""
+		if d.HostApi != nil {
+			hostAPI = d.HostApi.Name
+		}
+		fmt.Printf("[%d] %s (host: %s, in: %d, out: %d, rate: %.0f Hz)\n",
+			d.Index, d.Name, hostAPI, d.MaxInputChannels, d.MaxOutputChannels, d.DefaultSampleRate)
+	}
+}
+
+// runUninstall implements `camouflage uninstall`, tearing down whatever
+// virtual device Ensure() set up for system-jammer mode: the BlackHole
+// driver on macOS, or the null-sink module on Linux. This normally runs in
+// a separate process from the one that called Ensure, so each platform's
+// Teardown looks its own resources up by name rather than relying on
+// in-process state.
+func runUninstall() {
+	device := virtdev.New()
+	if err := device.Teardown(); err != nil {
+		log.Fatalf("Failed to remove virtual audio device: %v", err)
+	}
+	log.Println("Virtual audio device removed.")
+}
+
+// deviceConfig is the subset of Config persisted across runs, so --input
+// and --output don't need to be repeated on every invocation.
+type deviceConfig struct {
+	Input  string
+	Output string
+}
+
+// configPath returns the path to camouflage's config file, following the
+// XDG base directory spec (falling back to ~/.config if XDG_CONFIG_HOME is
+// unset, same as most Linux desktop tools).
+func configPath() (string, error) {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("could not determine home directory: %w", err)
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "camouflage", "config.toml"), nil
+}
+
+// loadDeviceConfig reads the persisted device selection. A missing file is
+// not an error; it just means no device has been selected yet.
+func loadDeviceConfig() (*deviceConfig, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return &deviceConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &deviceConfig{}
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.Trim(strings.TrimSpace(value), `"`)
+		switch key {
+		case "input":
+			cfg.Input = value
+		case "output":
+			cfg.Output = value
+		}
+	}
+	return cfg, nil
+}
+
+// saveDeviceConfig writes the device selection back to disk, creating the
+// config directory if needed.
+func saveDeviceConfig(cfg *deviceConfig) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "input = %q\n", cfg.Input)
+	fmt.Fprintf(&b, "output = %q\n", cfg.Output)
+	return os.WriteFile(path, []byte(b.String()), 0o644)
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeviceConfigRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	want := &deviceConfig{Input: "Virtual Mic", Output: "USB Speakers"}
+	if err := saveDeviceConfig(want); err != nil {
+		t.Fatalf("saveDeviceConfig: %v", err)
+	}
+
+	got, err := loadDeviceConfig()
+	if err != nil {
+		t.Fatalf("loadDeviceConfig: %v", err)
+	}
+	if *got != *want {
+		t.Errorf("loadDeviceConfig() = %+v, want %+v", got, want)
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "camouflage", "config.toml")); err != nil {
+		t.Errorf("expected config file to exist: %v", err)
+	}
+}
+
+func TestLoadDeviceConfigMissingFile(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+
+	cfg, err := loadDeviceConfig()
+	if err != nil {
+		t.Fatalf("loadDeviceConfig: %v", err)
+	}
+	if cfg.Input != "" || cfg.Output != "" {
+		t.Errorf("expected empty config, got %+v", cfg)
+	}
+}
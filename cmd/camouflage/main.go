@@ -5,26 +5,58 @@ import (
 	"flag"
 	"fmt"
 	"log"
+	"math"
 	"os"
 	"os/signal"
+	"sync/atomic"
 	"syscall"
 	"time"
 
 	"github.com/gordonklaus/portaudio"
+	"github.com/nomihq/camouflage/internal/denoise"
+	"github.com/nomihq/camouflage/internal/ringbuffer"
 	"github.com/nomihq/camouflage/internal/ultrasonic"
+	"github.com/nomihq/camouflage/internal/vad"
+	"github.com/nomihq/camouflage/internal/virtdev"
 )
 
 type Config struct {
 	Mode             string
+	SampleRate       int
 	UltrasonicFreq   float64
 	Duration         int
 	BufferSize       int
+	RingBufferFrames int
+	Denoise          bool
+	VAD              string
+	VADThreshold     float64
+	VADHangoverMS    int
+	Waveform         string
+	Harmonics        int
+	SweepStart       float64
+	SweepEnd         float64
+	SweepPeriodMS    int
+	InputDevice      string
+	OutputDevice     string
 	Verbose          bool
 }
 
 func main() {
+	// Verbs (list, uninstall) are dispatched before flag parsing so they
+	// don't have to coexist with --mode's flag-based config.
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "list":
+			runListDevices()
+			return
+		case "uninstall":
+			runUninstall()
+			return
+		}
+	}
+
 	config := parseFlags()
-	
+
 	if config.Verbose {
 		log.SetOutput(os.Stdout)
 	} else {
@@ -55,19 +87,63 @@ func main() {
 }
 
 func parseFlags() *Config {
+	persisted, err := loadDeviceConfig()
+	if err != nil {
+		log.Printf("Warning: could not load saved device config: %v", err)
+		persisted = &deviceConfig{}
+	}
+
 	config := &Config{
+		SampleRate:     ultrasonic.DefaultSampleRate,
 		UltrasonicFreq: 25000.0,
 		Duration:       1,
 		BufferSize:     1024,
+		VAD:            string(vad.BackendOff),
+		VADThreshold:   0.05,
+		VADHangoverMS:  300,
+		Waveform:       "sine",
+		Harmonics:      3,
+		SweepStart:     24000.0,
+		SweepEnd:       26000.0,
+		InputDevice:    persisted.Input,
+		OutputDevice:   persisted.Output,
 	}
-	
+
 	flag.StringVar(&config.Mode, "mode", "", "Mode: 'speaker' or 'system'")
-	flag.Float64Var(&config.UltrasonicFreq, "freq", config.UltrasonicFreq, "Ultrasonic frequency in Hz (default: 25000)")
+	flag.IntVar(&config.SampleRate, "sample-rate", config.SampleRate, "Sample rate in Hz used end-to-end for generation, playback, VAD, and denoising (default: 44100; use 48000 to match RNNoise's native rate and avoid --denoise resampling)")
+	flag.Float64Var(&config.UltrasonicFreq, "freq", config.UltrasonicFreq, "Ultrasonic frequency in Hz, used by the sine/multitone waveforms (default: 25000)")
 	flag.IntVar(&config.Duration, "duration", config.Duration, "Duration of ultrasonic loop in seconds (default: 1)")
 	flag.IntVar(&config.BufferSize, "buffer-size", config.BufferSize, "Audio buffer size (default: 1024)")
+	flag.IntVar(&config.RingBufferFrames, "ring-buffer-frames", 0, "Frames in the system-jammer input/output ring buffer (default: 3x buffer-size, rounded up to a power of two)")
+	flag.BoolVar(&config.Denoise, "denoise", false, "Suppress background/voice noise on outgoing mic audio before mixing in the ultrasonic tone (system mode only)")
+	flag.StringVar(&config.VAD, "vad", config.VAD, "Voice-activity gating for the ultrasonic carrier: 'off', 'energy', or 'webrtc' (system mode only)")
+	flag.Float64Var(&config.VADThreshold, "vad-threshold", config.VADThreshold, "RMS threshold above which the energy VAD backend considers a frame speech")
+	flag.IntVar(&config.VADHangoverMS, "vad-hangover-ms", config.VADHangoverMS, "How long the ultrasonic carrier keeps playing after speech stops, in milliseconds")
+	flag.StringVar(&config.Waveform, "waveform", config.Waveform, "Ultrasonic waveform: 'sine', 'multitone', 'sweep', or 'noise'")
+	flag.IntVar(&config.Harmonics, "harmonics", config.Harmonics, "Number of harmonics for the multitone waveform")
+	flag.Float64Var(&config.SweepStart, "sweep-start", config.SweepStart, "Sweep/noise-band start frequency in Hz")
+	flag.Float64Var(&config.SweepEnd, "sweep-end", config.SweepEnd, "Sweep/noise-band end frequency in Hz")
+	flag.IntVar(&config.SweepPeriodMS, "sweep-period-ms", 0, "Repeat the sweep waveform every N milliseconds instead of once across the full duration")
+	flag.StringVar(&config.InputDevice, "input", config.InputDevice, "PortAudio device name to capture system audio from in system mode (default: the virtual device, see 'camouflage list')")
+	flag.StringVar(&config.OutputDevice, "output", config.OutputDevice, "PortAudio device name to play through instead of the system default (see 'camouflage list')")
 	flag.BoolVar(&config.Verbose, "verbose", false, "Enable verbose logging")
 	flag.Parse()
-	
+
+	if config.SampleRate <= 0 {
+		log.Fatalf("Invalid sample rate: %d (must be positive)", config.SampleRate)
+	}
+
+	if config.InputDevice != persisted.Input || config.OutputDevice != persisted.Output {
+		if err := saveDeviceConfig(&deviceConfig{Input: config.InputDevice, Output: config.OutputDevice}); err != nil {
+			log.Printf("Warning: could not save device config: %v", err)
+		}
+	}
+
+	// Every generator/streamer in internal/ultrasonic that doesn't take an
+	// explicit rate reads the package-level ultrasonic.SampleRate, so this
+	// has to happen before any waveform is generated.
+	ultrasonic.SampleRate = config.SampleRate
+
 	return config
 }
 
@@ -86,19 +162,68 @@ func setupSignalHandling() (context.Context, context.CancelFunc) {
 	return ctx, cancel
 }
 
+// waveformSpec builds the ultrasonic.WaveformSpec describing config's
+// waveform selection, for validation ahead of generation.
+func waveformSpec(config *Config) ultrasonic.WaveformSpec {
+	return ultrasonic.WaveformSpec{
+		Kind:       config.Waveform,
+		Freq:       config.UltrasonicFreq,
+		Harmonics:  config.Harmonics,
+		RangeStart: config.SweepStart,
+		RangeEnd:   config.SweepEnd,
+	}
+}
+
+// generateWaveform dispatches to the ultrasonic generator matching
+// config.Waveform. "sweep" honors --sweep-period-ms, repeating the sweep
+// every N milliseconds instead of running it once across the full duration.
+func generateWaveform(config *Config) ([]float64, error) {
+	if err := ultrasonic.ValidateWaveformSpec(waveformSpec(config)); err != nil {
+		return nil, err
+	}
+
+	switch config.Waveform {
+	case "", "sine":
+		return ultrasonic.GenerateSineWave(config.UltrasonicFreq, config.Duration), nil
+	case "multitone":
+		return ultrasonic.GenerateMultiTone(config.UltrasonicFreq, config.Duration, config.Harmonics), nil
+	case "sweep":
+		if config.SweepPeriodMS > 0 {
+			return ultrasonic.GenerateRepeatingSweep(config.SweepStart, config.SweepEnd, config.SweepPeriodMS, config.Duration), nil
+		}
+		return ultrasonic.GenerateSweep(config.SweepStart, config.SweepEnd, config.Duration), nil
+	case "noise":
+		return ultrasonic.GenerateBandlimitedNoise(config.SweepStart, config.SweepEnd, config.Duration), nil
+	default:
+		return nil, fmt.Errorf("unknown waveform %q", config.Waveform)
+	}
+}
+
 // --- Speaker Jammer ---
 
 func runSpeakerJammer(ctx context.Context, config *Config) {
-	log.Printf("Speaker Jammer mode activated (freq: %.0f Hz)", config.UltrasonicFreq)
+	log.Printf("Speaker Jammer mode activated (waveform: %s, freq: %.0f Hz)", config.Waveform, config.UltrasonicFreq)
 
-	ultrasonicData := ultrasonic.GenerateSineWave(config.UltrasonicFreq, config.Duration)
+	ultrasonicData, err := generateWaveform(config)
+	if err != nil {
+		log.Fatalf("Invalid waveform configuration: %v", err)
+	}
 	if len(ultrasonicData) == 0 {
 		log.Fatal("Failed to generate ultrasonic data")
 	}
 	
 	pos := 0
 
-	stream, err := portaudio.OpenDefaultStream(0, 1, ultrasonic.SampleRate, 0, func(out []float32) {
+	outDevice, err := resolveOutputDevice(config)
+	if err != nil {
+		log.Fatalf("Failed to resolve output device: %v", err)
+	}
+
+	stream, err := portaudio.OpenStream(portaudio.StreamParameters{
+		Output:          portaudio.StreamDeviceParameters{Device: outDevice, Channels: 1},
+		SampleRate:      ultrasonic.SampleRate,
+		FramesPerBuffer: 0,
+	}, func(out []float32) {
 		for i := range out {
 			out[i] = float32(ultrasonicData[pos])
 			pos = (pos + 1) % len(ultrasonicData)
@@ -130,21 +255,21 @@ func runSpeakerJammer(ctx context.Context, config *Config) {
 // --- System Jammer ---
 
 func runSystemJammer(ctx context.Context, config *Config) {
-	log.Printf("On-System Jammer mode activated (freq: %.0f Hz)", config.UltrasonicFreq)
+	log.Printf("On-System Jammer mode activated (waveform: %s, freq: %.0f Hz)", config.Waveform, config.UltrasonicFreq)
 
-	if !checkForBlackHole() {
-		log.Println("BlackHole not found, attempting installation...")
-		if err := installBlackHole(); err != nil {
-			log.Fatalf("Error installing BlackHole: %v", err)
-		}
-		// Give the system time to recognize the new device
-		time.Sleep(2 * time.Second)
+	// Ensure() installs/loads the virtual device if needed; it is torn down
+	// separately via the `camouflage uninstall` verb, not on every run.
+	device := virtdev.New()
+	if err := device.Ensure(); err != nil {
+		log.Fatalf("Error preparing virtual audio device: %v", err)
 	}
+	// Give the system time to recognize a newly installed/loaded device.
+	time.Sleep(2 * time.Second)
 
 	log.Println("Starting audio processing...")
-	log.Println("Please set 'BlackHole 2ch' as your system's audio output.")
+	log.Printf("Please set '%s' as your system's audio output.", device.OutputDeviceName())
 
-	in, out, err := openSystemJammerStreams(config)
+	in, out, denoiser, err := openSystemJammerStreams(ctx, config, device)
 	if err != nil {
 		log.Fatalf("Failed to open system jammer streams: %v", err)
 	}
@@ -170,6 +295,12 @@ func runSystemJammer(ctx context.Context, config *Config) {
 		if err := out.Stop(); err != nil {
 			log.Printf("Warning: Failed to stop output stream: %v", err)
 		}
+		// in.Stop() above has returned by now, so the input callback (the
+		// only other user of denoiser) can no longer be running; it's safe
+		// to free the underlying RNNoise state.
+		if denoiser != nil {
+			denoiser.Close()
+		}
 	}()
 
 	log.Println("Audio loop running. Press Ctrl+C to stop.")
@@ -177,57 +308,246 @@ func runSystemJammer(ctx context.Context, config *Config) {
 	log.Println("System jammer stopped.")
 }
 
-func openSystemJammerStreams(config *Config) (*portaudio.Stream, *portaudio.Stream, error) {
-	blackhole, err := findDeviceByName(blackHoleDeviceName)
+// ringBufferSize picks the ring buffer capacity for the system jammer: an
+// explicit --ring-buffer-frames value if given, otherwise 2-4x the PortAudio
+// buffer size so the producer and consumer can drift without glitching.
+func ringBufferSize(config *Config) int {
+	if config.RingBufferFrames > 0 {
+		return config.RingBufferFrames
+	}
+	return config.BufferSize * 3
+}
+
+// logRingBufferStats periodically logs underflow/overflow counters while
+// --verbose is set, so drift between the input and output stream clocks is
+// visible instead of silently causing glitches.
+func logRingBufferStats(ctx context.Context, rb *ringbuffer.Float32) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			log.Printf("Ring buffer stats: underflows=%d overflows=%d", rb.Underflows(), rb.Overflows())
+		}
+	}
+}
+
+// vadGate smooths raw voice-activity decisions into a per-sample gain for
+// the ultrasonic carrier, with independent attack and release time
+// constants so the tone fades in quickly with speech but lingers briefly
+// (the hangover) after speech stops rather than chopping mid-sentence.
+//
+// setSpeaking is called from the input callback (the producer); next is
+// called from the output callback (the sole consumer) once per sample. The
+// two sides only communicate through the atomic speaking flag.
+type vadGate struct {
+	speaking int32 // atomic: 1 if the most recent VAD frame detected speech
+
+	hangoverSamples int
+	hangoverLeft    int // owned by next(); no synchronization needed
+	gain            float32
+	attackCoef      float32
+	releaseCoef     float32
+}
+
+func newVADGate(sampleRate int, hangover time.Duration) *vadGate {
+	return &vadGate{
+		hangoverSamples: int(hangover.Seconds() * float64(sampleRate)),
+		attackCoef:      gainCoef(10*time.Millisecond, sampleRate),
+		releaseCoef:     gainCoef(300*time.Millisecond, sampleRate),
+	}
+}
+
+// gainCoef returns the per-sample smoothing coefficient for an exponential
+// approach that reaches ~63% of the way to its target after duration d.
+func gainCoef(d time.Duration, sampleRate int) float32 {
+	if d <= 0 {
+		return 1
+	}
+	return float32(1 - math.Exp(-1/(d.Seconds()*float64(sampleRate))))
+}
+
+func (g *vadGate) setSpeaking(speaking bool) {
+	var v int32
+	if speaking {
+		v = 1
+	}
+	atomic.StoreInt32(&g.speaking, v)
+}
+
+// next advances the gate by one sample and returns the gain to multiply the
+// ultrasonic carrier sample by.
+func (g *vadGate) next() float32 {
+	if atomic.LoadInt32(&g.speaking) == 1 {
+		g.hangoverLeft = g.hangoverSamples
+	}
+
+	var target float32
+	if g.hangoverLeft > 0 {
+		target = 1
+		g.hangoverLeft--
+	}
+
+	coef := g.releaseCoef
+	if target > g.gain {
+		coef = g.attackCoef
+	}
+	g.gain += (target - g.gain) * coef
+	return g.gain
+}
+
+func openSystemJammerStreams(ctx context.Context, config *Config, device virtdev.VirtualDevice) (*portaudio.Stream, *portaudio.Stream, denoise.Denoiser, error) {
+	captureName := device.InputDeviceName()
+	if config.InputDevice != "" {
+		captureName = config.InputDevice
+	}
+	captureDevice, err := findDeviceByName(captureName)
 	if err != nil {
-		return nil, nil, fmt.Errorf("could not find BlackHole device: %w", err)
+		return nil, nil, nil, fmt.Errorf("could not find capture device %q: %w", captureName, err)
 	}
 
-	defaultOut, err := portaudio.DefaultOutputDevice()
+	defaultOut, err := resolveOutputDevice(config)
 	if err != nil {
-		return nil, nil, fmt.Errorf("could not get default output device: %w", err)
+		return nil, nil, nil, fmt.Errorf("could not resolve output device: %w", err)
 	}
 
-	ultrasonicData := ultrasonic.GenerateSineWave(config.UltrasonicFreq, config.Duration)
+	ultrasonicData, err := generateWaveform(config)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid waveform configuration: %w", err)
+	}
 	if len(ultrasonicData) == 0 {
-		return nil, nil, fmt.Errorf("failed to generate ultrasonic data")
+		return nil, nil, nil, fmt.Errorf("failed to generate ultrasonic data")
 	}
 	ultrasonicPos := 0
 
-	// This buffer will pass audio from the input stream to the output stream.
-	buffer := make([]float32, config.BufferSize)
+	// Lock-free SPSC ring buffer passing audio from the input callback
+	// (producer, runs on the virtual device's capture thread) to the output
+	// callback (consumer, runs on the speaker's render thread). The two
+	// callbacks run on independent PortAudio threads whose frame clocks
+	// drift relative to each other, so they must never share a buffer
+	// directly.
+	ring := ringbuffer.New(ringBufferSize(config))
+	if config.Verbose {
+		go logRingBufferStats(ctx, ring)
+	}
 
-	// Input stream (from BlackHole)
+	framesPerBuffer := config.BufferSize / 2
+
+	// Optional RNNoise denoising of outgoing mic audio, so the ultrasonic
+	// tone is mixed into a clean voice signal rather than a noisy one.
+	// Closing it is the caller's responsibility: it must only happen after
+	// the input stream (whose callback calls denoiser.ProcessFrame) has
+	// actually stopped, not on a separate, unsynchronized ctx.Done() — see
+	// runSystemJammer's cleanup.
+	var denoiser denoise.Denoiser
+	if config.Denoise {
+		denoiser, err = denoise.New()
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("could not initialize denoiser: %w", err)
+		}
+	}
+
+	denoiseFrameLen := ultrasonic.SampleRate / 100 // 10ms of mono audio
+	monoAccum := make([]float32, 0, denoiseFrameLen*2)
+	denoiseIn := make([]float32, denoiseFrameLen)
+	denoiseOut := make([]float32, denoiseFrameLen)
+
+	// Optional voice-activity gating so the ultrasonic carrier only plays
+	// while the user is actually speaking. feedVAD is a no-op until a
+	// non-off backend is configured.
+	var gate *vadGate
+	feedVAD := func(in []float32) {}
+	if vad.Backend(config.VAD) != vad.BackendOff && config.VAD != "" {
+		detector, err := vad.New(vad.Backend(config.VAD), ultrasonic.SampleRate, config.VADThreshold)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("could not initialize VAD: %w", err)
+		}
+		gate = newVADGate(ultrasonic.SampleRate, time.Duration(config.VADHangoverMS)*time.Millisecond)
+
+		vadFrameLen := ultrasonic.SampleRate / 50 // 20ms of mono audio
+		vadAccum := make([]float32, 0, vadFrameLen*2)
+		mono := make([]float32, 0, framesPerBuffer)
+
+		feedVAD = func(in []float32) {
+			mono = mono[:0]
+			for i := 0; i+1 < len(in); i += 2 {
+				mono = append(mono, (in[i]+in[i+1])/2)
+			}
+			vadAccum = append(vadAccum, mono...)
+			for len(vadAccum) >= vadFrameLen {
+				gate.setSpeaking(detector.Detect(vadAccum[:vadFrameLen]))
+				remaining := copy(vadAccum, vadAccum[vadFrameLen:])
+				vadAccum = vadAccum[:remaining]
+			}
+		}
+	}
+
+	// Input stream (from the virtual capture device)
 	inputStream, err := portaudio.OpenStream(portaudio.StreamParameters{
-		Input: portaudio.StreamDeviceParameters{Device: blackhole, Channels: 2},
+		Input:           portaudio.StreamDeviceParameters{Device: captureDevice, Channels: 2},
 		SampleRate:      ultrasonic.SampleRate,
-		FramesPerBuffer: len(buffer) / 2,
+		FramesPerBuffer: framesPerBuffer,
 	}, func(in []float32) {
-		if len(in) <= len(buffer) {
-			copy(buffer[:len(in)], in)
-		} else {
-			copy(buffer, in[:len(buffer)])
+		feedVAD(in)
+
+		if denoiser == nil {
+			ring.Write(in)
+			return
+		}
+
+		// Denoise requires mono 10ms frames; downmix the interleaved stereo
+		// input, denoise complete frames as they accumulate, and push the
+		// cleaned audio into the ring as stereo again.
+		for i := 0; i+1 < len(in); i += 2 {
+			monoAccum = append(monoAccum, (in[i]+in[i+1])/2)
+		}
+		for len(monoAccum) >= denoiseFrameLen {
+			copy(denoiseIn, monoAccum[:denoiseFrameLen])
+			if err := denoiser.ProcessFrame(denoiseIn, denoiseOut, ultrasonic.SampleRate); err != nil {
+				log.Printf("Warning: denoise failed, passing audio through: %v", err)
+				copy(denoiseOut, denoiseIn)
+			}
+			stereo := make([]float32, denoiseFrameLen*2)
+			for i, s := range denoiseOut {
+				stereo[2*i] = s
+				stereo[2*i+1] = s
+			}
+			ring.Write(stereo)
+
+			remaining := copy(monoAccum, monoAccum[denoiseFrameLen:])
+			monoAccum = monoAccum[:remaining]
 		}
 	})
 	if err != nil {
-		return nil, nil, fmt.Errorf("could not open input stream: %w", err)
+		return nil, nil, nil, fmt.Errorf("could not open input stream: %w", err)
 	}
 
 	// Output stream (to default speakers)
+	buffer := make([]float32, config.BufferSize)
 	outputStream, err := portaudio.OpenStream(portaudio.StreamParameters{
-		Output: portaudio.StreamDeviceParameters{Device: defaultOut, Channels: 2},
-		SampleRate:       ultrasonic.SampleRate,
-		FramesPerBuffer:  len(buffer) / 2,
+		Output:          portaudio.StreamDeviceParameters{Device: defaultOut, Channels: 2},
+		SampleRate:      ultrasonic.SampleRate,
+		FramesPerBuffer: framesPerBuffer,
 	}, func(out []float32) {
+		if len(out) > len(buffer) {
+			buffer = make([]float32, len(out))
+		}
+		// On ring underflow (the input stream has drifted behind), ReadFill
+		// pads with silence so we mix against quiet rather than stale frames.
+		ring.ReadFill(buffer[:len(out)])
+
+		var vadGain float32 = 1
 		for i := range out {
-			var inputSample float32
-			if i < len(buffer) {
-				inputSample = buffer[i]
+			if i%2 == 0 && gate != nil { // once per stereo frame
+				vadGain = gate.next()
 			}
 
 			// Mix input audio with ultrasonic signal
-			ultrasonicSample := float32(ultrasonicData[ultrasonicPos])
-			mixedSample := inputSample + ultrasonicSample*0.1 // Reduce ultrasonic volume
+			ultrasonicSample := float32(ultrasonicData[ultrasonicPos]) * vadGain
+			mixedSample := buffer[i] + ultrasonicSample*0.1 // Reduce ultrasonic volume
 
 			// Soft clipping to avoid harsh distortion
 			mixedSample = softClip(mixedSample)
@@ -241,10 +561,10 @@ func openSystemJammerStreams(config *Config) (*portaudio.Stream, *portaudio.Stre
 	})
 	if err != nil {
 		inputStream.Close()
-		return nil, nil, fmt.Errorf("could not open output stream: %w", err)
+		return nil, nil, nil, fmt.Errorf("could not open output stream: %w", err)
 	}
 
-	return inputStream, outputStream, nil
+	return inputStream, outputStream, denoiser, nil
 }
 
 // softClip applies a soft clipping function to prevent harsh distortion
@@ -257,6 +577,15 @@ func softClip(sample float32) float32 {
 	return sample
 }
 
+// resolveOutputDevice returns config.OutputDevice by name if set, otherwise
+// the system's default output device.
+func resolveOutputDevice(config *Config) (*portaudio.DeviceInfo, error) {
+	if config.OutputDevice != "" {
+		return findDeviceByName(config.OutputDevice)
+	}
+	return portaudio.DefaultOutputDevice()
+}
+
 func findDeviceByName(name string) (*portaudio.DeviceInfo, error) {
 	devices, err := portaudio.Devices()
 	if err != nil {
@@ -0,0 +1,39 @@
+// Package denoise suppresses background and voice noise from outgoing
+// microphone audio using Xiph's RNNoise, so the camouflage ultrasonic
+// carrier can be mixed into a clean voice signal rather than a noisy one.
+//
+// The RNNoise backend requires cgo and the native rnnoise library; see
+// rnnoise_cgo.go and rnnoise_nocgo.go for the two implementations of
+// newRNNoiseDenoiser this package picks between at build time, the same way
+// internal/vad isolates its cgo-only WebRTC backend.
+package denoise
+
+const (
+	// FrameSize is the number of samples RNNoise processes per call: 10ms
+	// of mono audio at its native SampleRate.
+	FrameSize = 480
+
+	// SampleRate is the sample rate RNNoise's model is trained for. Audio
+	// at other rates is resampled to and from this rate internally.
+	SampleRate = 48000
+)
+
+// Denoiser removes noise from mono audio one 10ms frame at a time. It is not
+// safe for concurrent use.
+type Denoiser interface {
+	// ProcessFrame denoises one 10ms frame of mono audio. in and out must
+	// each hold sampleRate/100 samples (e.g. 441 at 44100Hz); in is
+	// resampled to RNNoise's native 48kHz, denoised, and resampled back
+	// into out. in and out must not overlap.
+	ProcessFrame(in, out []float32, sampleRate int) error
+
+	// Close releases the underlying denoising state. It is safe to call
+	// more than once.
+	Close() error
+}
+
+// New allocates a new RNNoise-backed Denoiser. It returns an error if this
+// binary was built without cgo or the native rnnoise library.
+func New() (Denoiser, error) {
+	return newRNNoiseDenoiser()
+}
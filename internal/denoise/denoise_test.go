@@ -0,0 +1,52 @@
+package denoise
+
+import (
+	"math"
+	"testing"
+)
+
+func TestResampleLinearSameLength(t *testing.T) {
+	src := []float32{0.1, 0.2, 0.3, 0.4}
+	dst := make([]float32, len(src))
+	resampleLinear(src, dst)
+	for i, v := range src {
+		if dst[i] != v {
+			t.Errorf("dst[%d] = %f, want %f", i, dst[i], v)
+		}
+	}
+}
+
+func TestResampleLinearUpsample(t *testing.T) {
+	src := []float32{0.0, 1.0}
+	dst := make([]float32, 3)
+	resampleLinear(src, dst)
+
+	want := []float32{0.0, 0.5, 1.0}
+	for i := range want {
+		if math.Abs(float64(dst[i]-want[i])) > 1e-6 {
+			t.Errorf("dst[%d] = %f, want %f", i, dst[i], want[i])
+		}
+	}
+}
+
+func TestResampleLinearDownsample(t *testing.T) {
+	src := []float32{0.0, 0.5, 1.0}
+	dst := make([]float32, 2)
+	resampleLinear(src, dst)
+
+	want := []float32{0.0, 1.0}
+	for i := range want {
+		if math.Abs(float64(dst[i]-want[i])) > 1e-6 {
+			t.Errorf("dst[%d] = %f, want %f", i, dst[i], want[i])
+		}
+	}
+}
+
+func TestResampleLinearSingleSampleDst(t *testing.T) {
+	src := []float32{0.2, 0.4, 0.6}
+	dst := make([]float32, 1)
+	resampleLinear(src, dst)
+	if dst[0] != src[0] {
+		t.Errorf("dst[0] = %f, want %f", dst[0], src[0])
+	}
+}
@@ -0,0 +1,35 @@
+package denoise
+
+// resampleLinear resamples src into dst using linear interpolation. It is
+// good enough for RNNoise's short fixed-length frames; it is not intended as
+// a general-purpose audio resampler.
+//
+// Unlike the rest of this package, this file doesn't need cgo or the native
+// rnnoise library, so it (and its tests) build and run without either.
+func resampleLinear(src, dst []float32) {
+	if len(dst) == 0 {
+		return
+	}
+	if len(src) == len(dst) {
+		copy(dst, src)
+		return
+	}
+	if len(src) == 1 || len(dst) == 1 {
+		for i := range dst {
+			dst[i] = src[0]
+		}
+		return
+	}
+
+	ratio := float64(len(src)-1) / float64(len(dst)-1)
+	for i := range dst {
+		pos := float64(i) * ratio
+		lo := int(pos)
+		if lo >= len(src)-1 {
+			dst[i] = src[len(src)-1]
+			continue
+		}
+		frac := float32(pos - float64(lo))
+		dst[i] = src[lo] + (src[lo+1]-src[lo])*frac
+	}
+}
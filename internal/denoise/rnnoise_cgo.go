@@ -0,0 +1,62 @@
+//go:build cgo
+// +build cgo
+
+package denoise
+
+/*
+#cgo pkg-config: rnnoise
+#include <rnnoise.h>
+*/
+import "C"
+
+import "fmt"
+
+// rnnoiseDenoiser wraps an RNNoise DenoiseState.
+type rnnoiseDenoiser struct {
+	state *C.DenoiseState
+	rnIn  [FrameSize]float32
+	rnOut [FrameSize]float32
+}
+
+func newRNNoiseDenoiser() (Denoiser, error) {
+	state := C.rnnoise_create(nil)
+	if state == nil {
+		return nil, fmt.Errorf("denoise: rnnoise_create failed")
+	}
+	return &rnnoiseDenoiser{state: state}, nil
+}
+
+func (d *rnnoiseDenoiser) ProcessFrame(in, out []float32, sampleRate int) error {
+	frameLen := sampleRate / 100
+	if len(in) != frameLen || len(out) != frameLen {
+		return fmt.Errorf("denoise: frames must be %d samples (10ms @ %dHz), got in=%d out=%d", frameLen, sampleRate, len(in), len(out))
+	}
+
+	resampleLinear(in, d.rnIn[:])
+
+	// RNNoise expects samples in roughly the int16 range [-32768, 32767]
+	// rather than our normalized [-1, 1] float32 convention.
+	var cIn, cOut [FrameSize]C.float
+	for i, s := range d.rnIn {
+		cIn[i] = C.float(s * 32768.0)
+	}
+
+	C.rnnoise_process_frame(d.state, &cOut[0], &cIn[0])
+
+	for i, s := range cOut {
+		d.rnOut[i] = float32(s) / 32768.0
+	}
+
+	resampleLinear(d.rnOut[:], out)
+	return nil
+}
+
+// Close releases the underlying RNNoise state. It is safe to call more than
+// once.
+func (d *rnnoiseDenoiser) Close() error {
+	if d.state != nil {
+		C.rnnoise_destroy(d.state)
+		d.state = nil
+	}
+	return nil
+}
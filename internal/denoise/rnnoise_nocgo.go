@@ -0,0 +1,10 @@
+//go:build !cgo
+// +build !cgo
+
+package denoise
+
+import "fmt"
+
+func newRNNoiseDenoiser() (Denoiser, error) {
+	return nil, fmt.Errorf("denoise: built without rnnoise support (requires cgo)")
+}
@@ -0,0 +1,131 @@
+// Package ringbuffer provides a lock-free single-producer/single-consumer
+// ring buffer of audio frames, intended for passing samples between a
+// PortAudio input callback and a PortAudio output callback running on
+// separate realtime threads.
+package ringbuffer
+
+import "sync/atomic"
+
+// Float32 is a lock-free SPSC ring buffer of float32 audio samples. Exactly
+// one goroutine may call Write and exactly one (possibly different)
+// goroutine may call Read/ReadFill; the two sides never block each other.
+type Float32 struct {
+	buf   []float32
+	mask  uint64
+	write uint64 // atomic: index of the next sample the producer will write
+	read  uint64 // atomic: index of the next sample the consumer will read
+
+	underflows uint64 // atomic
+	overflows  uint64 // atomic
+}
+
+// New creates a ring buffer with capacity rounded up to the next power of
+// two that is at least size frames.
+func New(size int) *Float32 {
+	if size <= 0 {
+		size = 1
+	}
+	capacity := nextPowerOfTwo(size)
+	return &Float32{
+		buf:  make([]float32, capacity),
+		mask: uint64(capacity - 1),
+	}
+}
+
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// Cap returns the ring buffer's capacity in frames.
+func (r *Float32) Cap() int {
+	return len(r.buf)
+}
+
+// Write publishes samples into the ring buffer. The producer never blocks:
+// if the consumer has fallen behind far enough that samples would overwrite
+// unread data, the oldest unread samples are dropped and Overflows is
+// incremented.
+func (r *Float32) Write(samples []float32) {
+	if len(samples) == 0 {
+		return
+	}
+
+	capacity := uint64(len(r.buf))
+	if uint64(len(samples)) > capacity {
+		atomic.AddUint64(&r.overflows, 1)
+		samples = samples[uint64(len(samples))-capacity:]
+	}
+
+	write := atomic.LoadUint64(&r.write)
+	read := atomic.LoadUint64(&r.read)
+	if write-read+uint64(len(samples)) > capacity {
+		atomic.AddUint64(&r.overflows, 1)
+	}
+
+	for _, s := range samples {
+		r.buf[write&r.mask] = s
+		write++
+	}
+
+	atomic.StoreUint64(&r.write, write)
+}
+
+// Read consumes up to len(out) samples into out and returns how many samples
+// were actually available. If the producer has not written enough samples
+// yet, Underflows is incremented and only the available samples are copied.
+func (r *Float32) Read(out []float32) int {
+	read := atomic.LoadUint64(&r.read)
+	write := atomic.LoadUint64(&r.write)
+
+	capacity := uint64(len(r.buf))
+	available := write - read
+	if available > capacity {
+		// The producer has lapped us by more than one buffer's worth of
+		// samples since our last Read: the oldest unread samples here have
+		// already been overwritten. Skip read forward to the start of the
+		// most-recent window so we read in-order data instead of a stale,
+		// out-of-order mix of old and new slots.
+		read = write - capacity
+		available = capacity
+	}
+
+	n := uint64(len(out))
+	if available < n {
+		atomic.AddUint64(&r.underflows, 1)
+		n = available
+	}
+
+	for i := uint64(0); i < n; i++ {
+		out[i] = r.buf[read&r.mask]
+		read++
+	}
+
+	atomic.StoreUint64(&r.read, read)
+	return int(n)
+}
+
+// ReadFill behaves like Read, but pads any samples it could not supply with
+// silence so callers always receive a fully populated buffer rather than
+// stale data from a previous cycle.
+func (r *Float32) ReadFill(out []float32) {
+	n := r.Read(out)
+	for i := n; i < len(out); i++ {
+		out[i] = 0
+	}
+}
+
+// Underflows returns the number of Read calls that could not be fully
+// satisfied because the producer had not written enough samples yet.
+func (r *Float32) Underflows() uint64 {
+	return atomic.LoadUint64(&r.underflows)
+}
+
+// Overflows returns the number of Write calls that discarded samples because
+// the consumer had not kept up with the producer.
+func (r *Float32) Overflows() uint64 {
+	return atomic.LoadUint64(&r.overflows)
+}
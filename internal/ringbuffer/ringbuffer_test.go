@@ -0,0 +1,112 @@
+package ringbuffer
+
+import "testing"
+
+func TestNewRoundsUpToPowerOfTwo(t *testing.T) {
+	tests := []struct {
+		size    int
+		wantCap int
+	}{
+		{0, 1},
+		{1, 1},
+		{3, 4},
+		{1024, 1024},
+		{1025, 2048},
+	}
+
+	for _, tt := range tests {
+		rb := New(tt.size)
+		if rb.Cap() != tt.wantCap {
+			t.Errorf("New(%d).Cap() = %d, want %d", tt.size, rb.Cap(), tt.wantCap)
+		}
+	}
+}
+
+func TestWriteRead(t *testing.T) {
+	rb := New(8)
+
+	in := []float32{1, 2, 3, 4}
+	rb.Write(in)
+
+	out := make([]float32, 4)
+	n := rb.Read(out)
+	if n != 4 {
+		t.Fatalf("Read() = %d, want 4", n)
+	}
+	for i, v := range in {
+		if out[i] != v {
+			t.Errorf("out[%d] = %f, want %f", i, out[i], v)
+		}
+	}
+}
+
+func TestReadUnderflowCountsAndFills(t *testing.T) {
+	rb := New(8)
+	rb.Write([]float32{1, 2})
+
+	out := make([]float32, 4)
+	rb.ReadFill(out)
+
+	want := []float32{1, 2, 0, 0}
+	for i, v := range want {
+		if out[i] != v {
+			t.Errorf("out[%d] = %f, want %f", i, out[i], v)
+		}
+	}
+	if rb.Underflows() != 1 {
+		t.Errorf("Underflows() = %d, want 1", rb.Underflows())
+	}
+}
+
+func TestWriteOverflowCounts(t *testing.T) {
+	rb := New(4)
+
+	rb.Write([]float32{1, 2, 3, 4, 5, 6})
+	if rb.Overflows() != 1 {
+		t.Errorf("Overflows() = %d, want 1", rb.Overflows())
+	}
+
+	out := make([]float32, 4)
+	rb.ReadFill(out)
+	want := []float32{3, 4, 5, 6}
+	for i, v := range want {
+		if out[i] != v {
+			t.Errorf("out[%d] = %f, want %f", i, out[i], v)
+		}
+	}
+}
+
+func TestReadAfterCumulativeOverflowSkipsToMostRecentWindow(t *testing.T) {
+	rb := New(4)
+
+	rb.Write([]float32{1, 2})
+	rb.Write([]float32{3, 4, 5})
+
+	out := make([]float32, 4)
+	rb.ReadFill(out)
+
+	want := []float32{2, 3, 4, 5}
+	for i, v := range want {
+		if out[i] != v {
+			t.Errorf("out[%d] = %f, want %f", i, out[i], v)
+		}
+	}
+}
+
+func TestReadWriteWrapAround(t *testing.T) {
+	rb := New(4)
+
+	for cycle := 0; cycle < 3; cycle++ {
+		rb.Write([]float32{1, 2, 3})
+		out := make([]float32, 3)
+		rb.ReadFill(out)
+		for i, v := range []float32{1, 2, 3} {
+			if out[i] != v {
+				t.Errorf("cycle %d: out[%d] = %f, want %f", cycle, i, out[i], v)
+			}
+		}
+	}
+	if rb.Underflows() != 0 || rb.Overflows() != 0 {
+		t.Errorf("unexpected underflow/overflow: %d/%d", rb.Underflows(), rb.Overflows())
+	}
+}
@@ -4,10 +4,22 @@ package ultrasonic
 import (
 	"fmt"
 	"math"
+	"math/cmplx"
+	"math/rand"
+	"time"
 )
 
-// SampleRate is the number of samples per second.
-const SampleRate = 44100
+// DefaultSampleRate is the sample rate SampleRate starts out as.
+const DefaultSampleRate = 44100
+
+// SampleRate is the number of samples per second used by every generator
+// and streamer in this package that doesn't take an explicit rate via
+// WithSampleRate. It defaults to DefaultSampleRate (44.1kHz), but can be
+// overridden once at startup, before any audio is generated or streamed,
+// so a caller that needs a different rate end-to-end (commonly 48kHz, to
+// match RNNoise's native rate in the denoise pipeline and avoid an extra
+// resampling step) isn't stuck with 44.1kHz.
+var SampleRate = DefaultSampleRate
 
 // MinFreq is the minimum recommended frequency for ultrasonic jamming
 const MinFreq = 20000.0
@@ -17,24 +29,17 @@ const MaxFreq = 30000.0
 
 // GenerateSineWave generates a sine wave at a given frequency.
 // It returns a slice of float64 values, representing the raw audio data.
+//
+// It only accepts a whole number of seconds at full amplitude and zero
+// phase; for sub-second durations, an exact sample count, a different
+// amplitude, or a starting phase, use GenerateTone instead. For long or
+// open-ended playback, stream directly from NewSineTone to avoid the
+// upfront allocation.
 func GenerateSineWave(freq float64, durationSec int) []float64 {
 	if durationSec <= 0 {
 		return []float64{}
 	}
-	
-	numSamples := SampleRate * durationSec
-	if numSamples <= 0 {
-		return []float64{}
-	}
-	
-	data := make([]float64, numSamples)
-
-	for i := 0; i < numSamples; i++ {
-		angle := 2 * math.Pi * freq * float64(i) / SampleRate
-		data[i] = math.Sin(angle)
-	}
-
-	return data
+	return GenerateTone(freq, WithDuration(time.Duration(durationSec)*time.Second))
 }
 
 // ValidateFrequency checks if a frequency is suitable for ultrasonic jamming
@@ -51,57 +56,279 @@ func ValidateFrequency(freq float64) error {
 	return nil
 }
 
-// GenerateMultiTone generates multiple sine waves at different frequencies
-// and mixes them together for more effective jamming
-func GenerateMultiTone(baseFreq float64, durationSec int, harmonics int) []float64 {
-	if durationSec <= 0 || harmonics <= 0 {
+// WaveformSpec describes a selectable ultrasonic waveform, as exposed by the
+// CLI's --waveform flag family, for validation before generation.
+type WaveformSpec struct {
+	// Kind is "sine", "multitone", "sweep", or "noise".
+	Kind string
+	// Freq is the carrier frequency for "sine" and the base frequency for
+	// "multitone".
+	Freq float64
+	// Harmonics is the harmonic count for "multitone".
+	Harmonics int
+	// RangeStart and RangeEnd are the sweep endpoints for "sweep", or the
+	// band edges for "noise".
+	RangeStart float64
+	RangeEnd   float64
+}
+
+// ValidateWaveformSpec validates every frequency relevant to spec.Kind,
+// surfacing a clear error (e.g. a sweep endpoint above MaxFreq) instead of
+// letting the generator functions silently produce an out-of-band or
+// ineffective signal.
+func ValidateWaveformSpec(spec WaveformSpec) error {
+	switch spec.Kind {
+	case "", "sine":
+		return ValidateFrequency(spec.Freq)
+	case "multitone":
+		if spec.Harmonics <= 0 {
+			return fmt.Errorf("multitone waveform requires at least 1 harmonic, got %d", spec.Harmonics)
+		}
+		// GenerateMultiTone spreads harmonics 100Hz apart, so the highest
+		// one is what actually needs to stay in range.
+		topFreq := spec.Freq + float64(spec.Harmonics-1)*100
+		if err := ValidateFrequency(spec.Freq); err != nil {
+			return err
+		}
+		return ValidateFrequency(topFreq)
+	case "sweep", "noise":
+		if spec.RangeStart < 0 || spec.RangeEnd < 0 {
+			return fmt.Errorf("waveform frequencies cannot be negative: start=%.1f end=%.1f", spec.RangeStart, spec.RangeEnd)
+		}
+		if spec.RangeEnd <= spec.RangeStart {
+			return fmt.Errorf("range end (%.1f Hz) must be greater than range start (%.1f Hz)", spec.RangeEnd, spec.RangeStart)
+		}
+		if spec.RangeStart < MinFreq {
+			return fmt.Errorf("range start %.1f Hz is below the minimum %.1f Hz", spec.RangeStart, MinFreq)
+		}
+		if spec.RangeEnd > MaxFreq {
+			return fmt.Errorf("range end %.1f Hz exceeds the maximum %.1f Hz", spec.RangeEnd, MaxFreq)
+		}
+		return nil
+	default:
+		return fmt.Errorf("unknown waveform %q", spec.Kind)
+	}
+}
+
+// ToneSpec describes a single sine component to mix via MixTones.
+type ToneSpec struct {
+	Freq      float64
+	Amplitude float64
+	Phase     float64
+}
+
+// DefaultHeadroomDB is how far below full scale (0 dBFS) MixTones
+// normalizes its peak to, leaving margin for downstream mixing stages
+// (e.g. the ultrasonic/voice mix in the system jammer) before clipping.
+const DefaultHeadroomDB = -1.0
+
+// MixTones renders durationSec seconds of the sum of components and
+// normalizes the result so its true peak sits at DefaultHeadroomDB. Unlike
+// summing components at face-value amplitude, this guarantees the output
+// stays within [-1, 1] regardless of how many components are mixed or how
+// their phases happen to align.
+func MixTones(components []ToneSpec, durationSec int) []float64 {
+	if durationSec <= 0 || len(components) == 0 {
 		return []float64{}
 	}
-	
+
 	numSamples := SampleRate * durationSec
 	if numSamples <= 0 {
 		return []float64{}
 	}
-	
+
 	data := make([]float64, numSamples)
-	
+	peak := 0.0
 	for i := 0; i < numSamples; i++ {
 		var sample float64
-		for h := 1; h <= harmonics; h++ {
-			freq := baseFreq + float64(h-1)*100 // Spread frequencies 100Hz apart
-			angle := 2 * math.Pi * freq * float64(i) / SampleRate
-			amplitude := 1.0 / float64(harmonics) // Normalize amplitude
-			sample += amplitude * math.Sin(angle)
+		for _, c := range components {
+			angle := 2*math.Pi*c.Freq*float64(i)/float64(SampleRate) + c.Phase
+			sample += c.Amplitude * math.Sin(angle)
 		}
 		data[i] = sample
+		if abs := math.Abs(sample); abs > peak {
+			peak = abs
+		}
+	}
+
+	if peak > 0 {
+		headroom := math.Pow(10, DefaultHeadroomDB/20)
+		scale := headroom / peak
+		for i := range data {
+			data[i] *= scale
+		}
 	}
-	
+
 	return data
 }
 
-// GenerateSweep generates a frequency sweep from startFreq to endFreq
-// This can be more effective against adaptive noise cancellation
+// GenerateMultiTone generates multiple sine waves, spaced 100Hz apart
+// starting at baseFreq, and mixes them together for more effective
+// jamming.
+//
+// It is a thin wrapper over MixTones, which guarantees the mixed output
+// stays within [-1, 1] regardless of harmonic count or phase alignment.
+func GenerateMultiTone(baseFreq float64, durationSec int, harmonics int) []float64 {
+	if harmonics <= 0 {
+		return []float64{}
+	}
+
+	components := make([]ToneSpec, harmonics)
+	for h := 0; h < harmonics; h++ {
+		components[h] = ToneSpec{Freq: baseFreq + float64(h)*100, Amplitude: 1.0 / float64(harmonics)}
+	}
+	return MixTones(components, durationSec)
+}
+
+// GenerateSweep generates a frequency sweep from startFreq to endFreq.
+// This can be more effective against adaptive noise cancellation.
+//
+// It is a thin wrapper over NewSweep; see that constructor to stream the
+// sweep instead of allocating the whole signal up front.
 func GenerateSweep(startFreq, endFreq float64, durationSec int) []float64 {
 	if durationSec <= 0 {
 		return []float64{}
 	}
-	
+
 	numSamples := SampleRate * durationSec
 	if numSamples <= 0 {
 		return []float64{}
 	}
-	
+
 	data := make([]float64, numSamples)
-	freqRange := endFreq - startFreq
-	
+	NewSweep(startFreq, endFreq, durationSec).Stream(data)
+	return data
+}
+
+// GenerateRepeatingSweep generates a frequency sweep from startFreq to
+// endFreq that repeats every periodMs milliseconds, for durationSec
+// seconds.
+//
+// It is a thin wrapper over NewRepeatingSweep; see that constructor to
+// stream the sweep instead of allocating the whole signal up front.
+func GenerateRepeatingSweep(startFreq, endFreq float64, periodMs, durationSec int) []float64 {
+	if durationSec <= 0 || periodMs <= 0 {
+		return []float64{}
+	}
+
+	numSamples := SampleRate * durationSec
+	if numSamples <= 0 {
+		return []float64{}
+	}
+
+	data := make([]float64, numSamples)
+	NewRepeatingSweep(startFreq, endFreq, periodMs, durationSec).Stream(data)
+	return data
+}
+
+// GenerateBandlimitedNoise produces white noise FFT-filtered to only
+// contain energy between lowHz and highHz. Because it has no fixed
+// periodic structure, this is substantially harder for adaptive noise
+// cancellation to track than any deterministic tone or sweep.
+func GenerateBandlimitedNoise(lowHz, highHz float64, durationSec int) []float64 {
+	if durationSec <= 0 || lowHz < 0 || highHz <= lowHz {
+		return []float64{}
+	}
+
+	numSamples := SampleRate * durationSec
+	if numSamples <= 0 {
+		return []float64{}
+	}
+
+	fftSize := nextPowerOfTwo(numSamples)
+	spectrum := make([]complex128, fftSize)
+	for i := 0; i < numSamples; i++ {
+		spectrum[i] = complex(rand.Float64()*2-1, 0)
+	}
+
+	fft(spectrum, false)
+	for i := range spectrum {
+		freq := binFrequency(i, fftSize)
+		if freq < lowHz || freq > highHz {
+			spectrum[i] = 0
+		}
+	}
+	fft(spectrum, true)
+
+	data := make([]float64, numSamples)
+	peak := 0.0
 	for i := 0; i < numSamples; i++ {
-		// Linear frequency sweep
-		progress := float64(i) / float64(numSamples-1)
-		currentFreq := startFreq + freqRange*progress
-		
-		angle := 2 * math.Pi * currentFreq * float64(i) / SampleRate
-		data[i] = math.Sin(angle)
-	}
-	
+		v := real(spectrum[i])
+		data[i] = v
+		if abs := math.Abs(v); abs > peak {
+			peak = abs
+		}
+	}
+	if peak > 0 {
+		for i := range data {
+			data[i] /= peak
+		}
+	}
+
 	return data
 }
+
+// nextPowerOfTwo returns the smallest power of two that is >= n.
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// binFrequency returns the (always non-negative) frequency a DFT bin of a
+// real-valued, fftSize-point transform corresponds to, folding the upper
+// half of the spectrum (which represents negative frequencies) back down.
+func binFrequency(bin, fftSize int) float64 {
+	if bin > fftSize/2 {
+		bin -= fftSize
+	}
+	return math.Abs(float64(bin) * float64(SampleRate) / float64(fftSize))
+}
+
+// fft computes an in-place Cooley-Tukey radix-2 FFT of data. len(data) must
+// be a power of two. If inverse is true, it computes the inverse transform
+// (including the 1/N normalization) instead of the forward transform.
+func fft(data []complex128, inverse bool) {
+	n := len(data)
+	if n <= 1 {
+		return
+	}
+
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+
+	for length := 2; length <= n; length <<= 1 {
+		angle := 2 * math.Pi / float64(length)
+		if !inverse {
+			angle = -angle
+		}
+		step := cmplx.Exp(complex(0, angle))
+		for i := 0; i < n; i += length {
+			w := complex(1.0, 0.0)
+			half := length / 2
+			for j := 0; j < half; j++ {
+				u := data[i+j]
+				v := data[i+j+half] * w
+				data[i+j] = u + v
+				data[i+j+half] = u - v
+				w *= step
+			}
+		}
+	}
+
+	if inverse {
+		for i := range data {
+			data[i] /= complex(float64(n), 0)
+		}
+	}
+}
@@ -2,6 +2,7 @@
 package ultrasonic
 
 import (
+	"fmt"
 	"math"
 	"testing"
 )
@@ -80,22 +81,10 @@ func TestSampleRate(t *testing.T) {
 	}
 }
 
-func TestGenerateSineWave_Frequency(t *testing.T) {
-	// Test that the generated wave has approximately the correct frequency
-	freq := 1000.0 // 1kHz for easy testing
-	duration := 1
-	
-	data := GenerateSineWave(freq, duration)
-	
-	// Count zero crossings to estimate frequency
-	zeroCrossings := countZeroCrossings(data)
-	estimatedFreq := float64(zeroCrossings) / 2.0 // Each cycle has 2 zero crossings
-	
-	tolerance := 1.0 // Allow 1 Hz tolerance
-	if math.Abs(estimatedFreq-freq) > tolerance {
-		t.Errorf("Expected frequency ~%.1f Hz, estimated %.1f Hz", freq, estimatedFreq)
-	}
-}
+// Frequency accuracy is covered by TestGenerateSineWave_FrequencyGoertzel in
+// stats_test.go, which uses a Goertzel peak search instead of counting zero
+// crossings — the zero-crossing estimate only stayed accurate well below
+// the ultrasonic band.
 
 func TestGenerateSineWave_Amplitude(t *testing.T) {
 	freq := 1000.0
@@ -163,21 +152,6 @@ func validateSineWaveProperties(t *testing.T, data []float64, freq float64, dura
 	}
 }
 
-// Helper function to count zero crossings
-func countZeroCrossings(data []float64) int {
-	if len(data) < 2 {
-		return 0
-	}
-	
-	crossings := 0
-	for i := 1; i < len(data); i++ {
-		if (data[i-1] < 0 && data[i] >= 0) || (data[i-1] >= 0 && data[i] < 0) {
-			crossings++
-		}
-	}
-	return crossings
-}
-
 func BenchmarkGenerateSineWave(b *testing.B) {
 	freq := 25000.0
 	duration := 1
@@ -248,10 +222,11 @@ func TestGenerateMultiTone(t *testing.T) {
 				t.Errorf("GenerateMultiTone() length = %d, want %d", len(data), tt.wantLen)
 			}
 			
-			// Check amplitude range
+			// MixTones normalizes to DefaultHeadroomDB, so the mix is
+			// strictly within [-1, 1] regardless of harmonic count.
 			for i, val := range data {
-				if val < -1.1 || val > 1.1 { // Allow slight overshoot due to mixing
-					t.Errorf("Sample %d out of reasonable range: %f", i, val)
+				if val < -1.0 || val > 1.0 {
+					t.Errorf("Sample %d out of range [-1.0, 1.0]: %f", i, val)
 					break
 				}
 			}
@@ -259,6 +234,44 @@ func TestGenerateMultiTone(t *testing.T) {
 	}
 }
 
+func TestGenerateMultiTone_StrictAmplitudeBound(t *testing.T) {
+	for _, harmonics := range []int{1, 3, 5, 10} {
+		t.Run(fmt.Sprintf("%d harmonics", harmonics), func(t *testing.T) {
+			data := GenerateMultiTone(25000.0, 1, harmonics)
+			for i, val := range data {
+				if math.Abs(val) > 1.0 {
+					t.Fatalf("sample %d exceeds unit amplitude: %f", i, val)
+				}
+			}
+		})
+	}
+}
+
+func TestMixTones(t *testing.T) {
+	data := MixTones([]ToneSpec{
+		{Freq: 25000.0, Amplitude: 1.0},
+		{Freq: 25100.0, Amplitude: 1.0},
+	}, 1)
+
+	if len(data) != SampleRate {
+		t.Fatalf("MixTones() length = %d, want %d", len(data), SampleRate)
+	}
+	for i, val := range data {
+		if math.Abs(val) > 1.0 {
+			t.Fatalf("sample %d exceeds unit amplitude: %f", i, val)
+		}
+	}
+}
+
+func TestMixTonesEdgeCases(t *testing.T) {
+	if data := MixTones(nil, 1); len(data) != 0 {
+		t.Errorf("expected empty data for no components, got %d samples", len(data))
+	}
+	if data := MixTones([]ToneSpec{{Freq: 25000.0, Amplitude: 1.0}}, 0); len(data) != 0 {
+		t.Errorf("expected empty data for zero duration, got %d samples", len(data))
+	}
+}
+
 func TestGenerateSweep(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -293,6 +306,24 @@ func TestGenerateSweep(t *testing.T) {
 	}
 }
 
+func TestGenerateRepeatingSweepContinuity(t *testing.T) {
+	data := GenerateRepeatingSweep(24000, 26000, 10, 1)
+	if len(data) == 0 {
+		t.Fatal("expected non-empty sweep data")
+	}
+	for _, v := range data {
+		if v < -1.0001 || v > 1.0001 {
+			t.Fatalf("sample out of range: %f", v)
+		}
+	}
+}
+
+func TestGenerateRepeatingSweepZeroDuration(t *testing.T) {
+	if data := GenerateRepeatingSweep(24000, 26000, 10, 0); len(data) != 0 {
+		t.Fatalf("expected empty data for zero duration, got %d samples", len(data))
+	}
+}
+
 func TestConstants(t *testing.T) {
 	if MinFreq >= MaxFreq {
 		t.Error("MinFreq should be less than MaxFreq")
@@ -322,9 +353,75 @@ func BenchmarkGenerateSweep(b *testing.B) {
 	startFreq := 24000.0
 	endFreq := 26000.0
 	duration := 1
-	
+
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		GenerateSweep(startFreq, endFreq, duration)
 	}
 }
+
+func TestGenerateBandlimitedNoise(t *testing.T) {
+	tests := []struct {
+		name     string
+		lowHz    float64
+		highHz   float64
+		duration int
+		wantLen  int
+	}{
+		{"standard band, 1 second", 24000.0, 26000.0, 1, SampleRate},
+		{"wide band, 2 seconds", 20000.0, 30000.0, 2, SampleRate * 2},
+		{"zero duration", 24000.0, 26000.0, 0, 0},
+		{"inverted band", 26000.0, 24000.0, 1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := GenerateBandlimitedNoise(tt.lowHz, tt.highHz, tt.duration)
+
+			if len(data) != tt.wantLen {
+				t.Fatalf("GenerateBandlimitedNoise() length = %d, want %d", len(data), tt.wantLen)
+			}
+			for i, val := range data {
+				if val < -1.0 || val > 1.0 {
+					t.Errorf("Sample %d out of range [-1.0, 1.0]: %f", i, val)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestValidateWaveformSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    WaveformSpec
+		wantErr bool
+	}{
+		{"valid sine", WaveformSpec{Kind: "sine", Freq: 25000.0}, false},
+		{"sine too low", WaveformSpec{Kind: "sine", Freq: 1000.0}, true},
+		{"valid multitone", WaveformSpec{Kind: "multitone", Freq: 25000.0, Harmonics: 3}, false},
+		{"multitone top harmonic too high", WaveformSpec{Kind: "multitone", Freq: 29950.0, Harmonics: 5}, true},
+		{"multitone zero harmonics", WaveformSpec{Kind: "multitone", Freq: 25000.0, Harmonics: 0}, true},
+		{"valid sweep", WaveformSpec{Kind: "sweep", RangeStart: 24000.0, RangeEnd: 26000.0}, false},
+		{"sweep end above max", WaveformSpec{Kind: "sweep", RangeStart: 24000.0, RangeEnd: 35000.0}, true},
+		{"sweep end before start", WaveformSpec{Kind: "sweep", RangeStart: 26000.0, RangeEnd: 24000.0}, true},
+		{"valid noise band", WaveformSpec{Kind: "noise", RangeStart: 20000.0, RangeEnd: 30000.0}, false},
+		{"unknown waveform", WaveformSpec{Kind: "granular"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateWaveformSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateWaveformSpec() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func BenchmarkGenerateBandlimitedNoise(b *testing.B) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		GenerateBandlimitedNoise(24000.0, 26000.0, 1)
+	}
+}
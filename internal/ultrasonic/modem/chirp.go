@@ -0,0 +1,106 @@
+package modem
+
+import (
+	"fmt"
+	"math"
+)
+
+// Chirp modulates bits as linear frequency sweeps between Low and High: an
+// up-sweep for a 1 bit, a down-sweep for a 0 bit.
+//
+// This mirrors ultrasonic.GenerateSweep's phase-accumulation approach, but
+// at an explicit sample count rather than a whole number of seconds, since
+// a single symbol is almost always shorter than one second.
+type Chirp struct {
+	Low, High  float64
+	SampleRate int
+	Opts       Options
+}
+
+// NewChirp returns a Chirp modulator/demodulator sweeping between low and
+// high at sampleRate.
+func NewChirp(low, high float64, sampleRate int, opts Options) *Chirp {
+	return &Chirp{Low: low, High: high, SampleRate: sampleRate, Opts: opts}
+}
+
+// chirpSymbol renders n samples sweeping linearly from startFreq to
+// endFreq.
+func chirpSymbol(startFreq, endFreq float64, n, sampleRate int) []float64 {
+	samples := make([]float64, n)
+	freqRange := endFreq - startFreq
+	phase := 0.0
+	for i := 0; i < n; i++ {
+		var progress float64
+		if n > 1 {
+			progress = float64(i) / float64(n-1)
+		}
+		currentFreq := startFreq + freqRange*progress
+
+		samples[i] = math.Sin(phase)
+		phase += 2 * math.Pi * currentFreq / float64(sampleRate)
+		if phase >= 2*math.Pi {
+			phase -= 2 * math.Pi
+		}
+	}
+	return samples
+}
+
+func (c *Chirp) Modulate(data []byte) []float64 {
+	bits := buildFrame(data)
+	symbolLen := c.Opts.symbolLen(c.SampleRate)
+	env := shapeEnvelope(c.Opts.Shape, symbolLen)
+
+	out := make([]float64, 0, len(bits)*symbolLen)
+	for _, bit := range bits {
+		symbol := chirpSymbol(c.High, c.Low, symbolLen, c.SampleRate)
+		if bit == 1 {
+			symbol = chirpSymbol(c.Low, c.High, symbolLen, c.SampleRate)
+		}
+		for i, v := range symbol {
+			out = append(out, env[i]*v)
+		}
+	}
+	return out
+}
+
+// decodeBits matches each symbol window against reference up-sweep and
+// down-sweep templates via simple correlation (dot product), picking
+// whichever direction correlates more strongly. Each symbol is judged
+// independently, so findFrameStart can call this on arbitrary offsets.
+func (c *Chirp) decodeBits(samples []float64) []int {
+	symbolLen := c.Opts.symbolLen(c.SampleRate)
+	up := chirpSymbol(c.Low, c.High, symbolLen, c.SampleRate)
+	down := chirpSymbol(c.High, c.Low, symbolLen, c.SampleRate)
+
+	numSymbols := len(samples) / symbolLen
+	bits := make([]int, numSymbols)
+	for s := 0; s < numSymbols; s++ {
+		window := samples[s*symbolLen : (s+1)*symbolLen]
+		if correlate(window, up) > correlate(window, down) {
+			bits[s] = 1
+		}
+	}
+	return bits
+}
+
+func (c *Chirp) Demodulate(samples []float64) ([]byte, error) {
+	symbolLen, err := c.Opts.validateSymbolLen(c.SampleRate)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, ok := findFrameStart(samples, symbolLen, c.decodeBits)
+	if !ok {
+		return nil, fmt.Errorf("modem: signal too short to contain a preamble")
+	}
+
+	return parseFrame(c.decodeBits(samples[offset:]))
+}
+
+func correlate(a, b []float64) float64 {
+	var sum float64
+	for i := range a {
+		sum += a[i] * b[i]
+	}
+	return sum
+}
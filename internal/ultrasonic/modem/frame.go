@@ -0,0 +1,171 @@
+package modem
+
+import "fmt"
+
+const (
+	preambleBytes = 4    // alternating 0xAA bytes, for receiver clock recovery
+	sofByte       = 0x7E // start-of-frame marker distinct from the alternating preamble
+	headerBytes   = preambleBytes + 1 + 2
+)
+
+// crc16 computes the CRC-16/CCITT-FALSE checksum of data.
+func crc16(data []byte) uint16 {
+	var crc uint16 = 0xFFFF
+	for _, b := range data {
+		crc ^= uint16(b) << 8
+		for i := 0; i < 8; i++ {
+			if crc&0x8000 != 0 {
+				crc = (crc << 1) ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
+
+func bytesToBits(data []byte) []int {
+	bits := make([]int, 0, len(data)*8)
+	for _, b := range data {
+		for i := 7; i >= 0; i-- {
+			bits = append(bits, int((b>>uint(i))&1))
+		}
+	}
+	return bits
+}
+
+func bitsToBytes(bits []int) []byte {
+	out := make([]byte, (len(bits)+7)/8)
+	for i, bit := range bits {
+		if bit != 0 {
+			out[i/8] |= 1 << uint(7-i%8)
+		}
+	}
+	return out
+}
+
+// buildFrame wraps payload with a preamble, a start-of-frame marker, a
+// 16-bit length field, and a trailing CRC-16, and returns the whole frame
+// as individual bits ready for a symbol encoder.
+func buildFrame(payload []byte) []int {
+	frame := make([]byte, 0, headerBytes+len(payload)+2)
+	for i := 0; i < preambleBytes; i++ {
+		frame = append(frame, 0xAA)
+	}
+	frame = append(frame, sofByte)
+	frame = append(frame, byte(len(payload)>>8), byte(len(payload)))
+	frame = append(frame, payload...)
+	crc := crc16(payload)
+	frame = append(frame, byte(crc>>8), byte(crc))
+	return bytesToBits(frame)
+}
+
+// preamblePattern returns the bit pattern buildFrame's preamble encodes
+// (0xAA repeated preambleBytes times): alternating 1/0 bits that a
+// Demodulator can correlate against to find frame start before decoding.
+func preamblePattern() []int {
+	b := make([]byte, preambleBytes)
+	for i := range b {
+		b[i] = 0xAA
+	}
+	return bytesToBits(b)
+}
+
+// scoreOffset decodes the len(preamble) symbols starting at offset and
+// returns how many of them match the known preamble bit pattern.
+func scoreOffset(samples []float64, offset, need int, preamble []int, decodeBits func([]float64) []int) int {
+	got := decodeBits(samples[offset : offset+need])
+	score := 0
+	for i, want := range preamble {
+		if got[i] == want {
+			score++
+		}
+	}
+	return score
+}
+
+// findFrameStart looks for the sample offset whose next len(preamble)
+// symbols, decoded by decodeBits, best match the known preamble bit
+// pattern. Captured audio generally has leading silence or an offset that
+// isn't aligned to a symbol boundary, so Demodulate can't assume samples
+// starts exactly at frame 0 the way modem_test.go's direct
+// Modulate-to-Demodulate round trip does; this is what the preamble exists
+// for. It reports false if samples is too short to contain a full preamble
+// anywhere.
+//
+// Scoring every single-sample offset with a full preamble-length decode is
+// O(len(samples) * len(preamble) * symbolLen), which at realistic capture
+// lengths and the default baud is far too slow. Instead this searches
+// symbolLen-wide strides first to find the best symbol-aligned candidate,
+// then refines sample-by-sample only in a +/-symbolLen window around it,
+// bounding the expensive part of the search to a constant amount of work
+// independent of len(samples).
+func findFrameStart(samples []float64, symbolLen int, decodeBits func([]float64) []int) (int, bool) {
+	preamble := preamblePattern()
+	need := len(preamble) * symbolLen
+	if len(samples) < need {
+		return 0, false
+	}
+
+	coarseBest, coarseScore := 0, -1
+	for offset := 0; offset+need <= len(samples); offset += symbolLen {
+		if score := scoreOffset(samples, offset, need, preamble, decodeBits); score > coarseScore {
+			coarseScore, coarseBest = score, offset
+			if score == len(preamble) {
+				break
+			}
+		}
+	}
+
+	lo := coarseBest - symbolLen
+	if lo < 0 {
+		lo = 0
+	}
+	hi := coarseBest + symbolLen
+	if hi+need > len(samples) {
+		hi = len(samples) - need
+	}
+
+	bestOffset, bestScore := coarseBest, -1
+	for offset := lo; offset <= hi; offset++ {
+		score := scoreOffset(samples, offset, need, preamble, decodeBits)
+		if score > bestScore {
+			bestScore, bestOffset = score, offset
+		}
+		if score == len(preamble) {
+			break
+		}
+	}
+	return bestOffset, true
+}
+
+// parseFrame extracts and validates the payload from a decoded bitstream:
+// it checks the start-of-frame marker, trusts the length field to find the
+// payload boundary, and verifies the trailing CRC-16.
+func parseFrame(bits []int) ([]byte, error) {
+	headerBits := headerBytes * 8
+	if len(bits) < headerBits {
+		return nil, fmt.Errorf("modem: frame too short to contain a header (%d bits)", len(bits))
+	}
+
+	header := bitsToBytes(bits[:headerBits])
+	if header[preambleBytes] != sofByte {
+		return nil, fmt.Errorf("modem: start-of-frame marker not found")
+	}
+	length := int(header[preambleBytes+1])<<8 | int(header[preambleBytes+2])
+
+	payloadEnd := headerBits + length*8
+	crcEnd := payloadEnd + 16
+	if len(bits) < crcEnd {
+		return nil, fmt.Errorf("modem: frame too short for declared payload length %d", length)
+	}
+
+	payload := bitsToBytes(bits[headerBits:payloadEnd])
+	crcBytes := bitsToBytes(bits[payloadEnd:crcEnd])
+	gotCRC := uint16(crcBytes[0])<<8 | uint16(crcBytes[1])
+	if wantCRC := crc16(payload); gotCRC != wantCRC {
+		return nil, fmt.Errorf("modem: CRC mismatch (got %04x, want %04x)", gotCRC, wantCRC)
+	}
+
+	return payload, nil
+}
@@ -0,0 +1,71 @@
+package modem
+
+import (
+	"fmt"
+	"math"
+)
+
+// FSK modulates bits by switching between two carriers: Freq0 for a 0 bit,
+// Freq1 for a 1 bit.
+type FSK struct {
+	Freq0, Freq1 float64
+	SampleRate   int
+	Opts         Options
+}
+
+// NewFSK returns an FSK modulator/demodulator using freq0/freq1 as the 0/1
+// carriers at sampleRate.
+func NewFSK(freq0, freq1 float64, sampleRate int, opts Options) *FSK {
+	return &FSK{Freq0: freq0, Freq1: freq1, SampleRate: sampleRate, Opts: opts}
+}
+
+func (f *FSK) Modulate(data []byte) []float64 {
+	bits := buildFrame(data)
+	symbolLen := f.Opts.symbolLen(f.SampleRate)
+	env := shapeEnvelope(f.Opts.Shape, symbolLen)
+
+	out := make([]float64, 0, len(bits)*symbolLen)
+	for _, bit := range bits {
+		freq := f.Freq0
+		if bit == 1 {
+			freq = f.Freq1
+		}
+		for i := 0; i < symbolLen; i++ {
+			angle := 2 * math.Pi * freq * float64(i) / float64(f.SampleRate)
+			out = append(out, env[i]*math.Sin(angle))
+		}
+	}
+	return out
+}
+
+// decodeBits decides each symbol in samples independently by comparing the
+// Goertzel power of the two carriers; it has no dependency on where
+// samples starts, so findFrameStart can call it on arbitrary offsets.
+func (f *FSK) decodeBits(samples []float64) []int {
+	symbolLen := f.Opts.symbolLen(f.SampleRate)
+	numSymbols := len(samples) / symbolLen
+	bits := make([]int, numSymbols)
+	for s := 0; s < numSymbols; s++ {
+		window := samples[s*symbolLen : (s+1)*symbolLen]
+		p0 := goertzelPower(window, f.Freq0, f.SampleRate)
+		p1 := goertzelPower(window, f.Freq1, f.SampleRate)
+		if p1 > p0 {
+			bits[s] = 1
+		}
+	}
+	return bits
+}
+
+func (f *FSK) Demodulate(samples []float64) ([]byte, error) {
+	symbolLen, err := f.Opts.validateSymbolLen(f.SampleRate)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, ok := findFrameStart(samples, symbolLen, f.decodeBits)
+	if !ok {
+		return nil, fmt.Errorf("modem: signal too short to contain a preamble")
+	}
+
+	return parseFrame(f.decodeBits(samples[offset:]))
+}
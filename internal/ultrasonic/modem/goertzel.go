@@ -0,0 +1,42 @@
+package modem
+
+import "math"
+
+// goertzelPower returns the power of samples at targetFreq (Hz) using the
+// Goertzel algorithm: three multiply-adds per sample to recover the power
+// at a single frequency bin, far cheaper than a full FFT when the
+// demodulator only ever needs one or two known carriers.
+func goertzelPower(samples []float64, targetFreq float64, sampleRate int) float64 {
+	n := len(samples)
+	if n == 0 {
+		return 0
+	}
+
+	k := int(0.5 + float64(n)*targetFreq/float64(sampleRate))
+	omega := 2 * math.Pi * float64(k) / float64(n)
+	coeff := 2 * math.Cos(omega)
+
+	var s0, s1, s2 float64
+	for _, x := range samples {
+		s0 = x + coeff*s1 - s2
+		s2 = s1
+		s1 = s0
+	}
+	return s1*s1 + s2*s2 - coeff*s1*s2
+}
+
+// shapeEnvelope returns the per-sample amplitude envelope for shaping a
+// symbol window of n samples.
+func shapeEnvelope(shape Shape, n int) []float64 {
+	env := make([]float64, n)
+	if shape != ShapeRaisedCosine || n <= 1 {
+		for i := range env {
+			env[i] = 1
+		}
+		return env
+	}
+	for i := range env {
+		env[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(n-1)))
+	}
+	return env
+}
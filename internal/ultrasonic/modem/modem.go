@@ -0,0 +1,60 @@
+// Package modem encodes and decodes arbitrary byte streams over the
+// ultrasonic band, so a sender and receiver running camouflage can exchange
+// short messages inaudibly alongside (or instead of) the jamming carrier.
+// Three schemes are provided: 2-FSK, on-off keying, and chirp (up/down
+// sweep) encoding.
+package modem
+
+import "fmt"
+
+// Modulator encodes a byte payload as a signal.
+type Modulator interface {
+	Modulate(data []byte) []float64
+}
+
+// Demodulator decodes a signal back into the byte payload it carries,
+// returning an error if the frame's CRC-16 doesn't check out or no valid
+// frame is found.
+type Demodulator interface {
+	Demodulate(samples []float64) ([]byte, error)
+}
+
+// Shape controls how a symbol's amplitude envelope is shaped across its
+// window. ShapeRaw applies no shaping; ShapeRaisedCosine tapers each
+// symbol's edges to reduce the spectral splatter a hard on/off or
+// frequency transition would otherwise cause.
+type Shape int
+
+const (
+	ShapeRaw Shape = iota
+	ShapeRaisedCosine
+)
+
+// DefaultBaud is the symbol rate used when Options.Baud is unset.
+const DefaultBaud = 50
+
+// Options configures a Modulator/Demodulator pair's symbol timing and
+// shaping. The zero value uses DefaultBaud and ShapeRaw.
+type Options struct {
+	Baud  int
+	Shape Shape
+}
+
+func (o Options) baud() int {
+	if o.Baud <= 0 {
+		return DefaultBaud
+	}
+	return o.Baud
+}
+
+func (o Options) symbolLen(sampleRate int) int {
+	return sampleRate / o.baud()
+}
+
+func (o Options) validateSymbolLen(sampleRate int) (int, error) {
+	n := o.symbolLen(sampleRate)
+	if n <= 0 {
+		return 0, fmt.Errorf("modem: symbol length must be positive (baud %d at %d Hz)", o.baud(), sampleRate)
+	}
+	return n, nil
+}
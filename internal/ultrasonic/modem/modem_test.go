@@ -0,0 +1,183 @@
+package modem
+
+import (
+	"bytes"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+const testSampleRate = 48000
+
+func randomPayload(seed int64, n int) []byte {
+	r := rand.New(rand.NewSource(seed))
+	data := make([]byte, n)
+	r.Read(data)
+	return data
+}
+
+func TestFSKRoundTrip(t *testing.T) {
+	payload := randomPayload(1, 16)
+	m := NewFSK(20000, 21000, testSampleRate, Options{Baud: 100})
+
+	signal := m.Modulate(payload)
+	got, err := m.Demodulate(signal)
+	if err != nil {
+		t.Fatalf("Demodulate returned error: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("round trip mismatch: got %x, want %x", got, payload)
+	}
+}
+
+func TestOOKRoundTrip(t *testing.T) {
+	payload := randomPayload(2, 16)
+	m := NewOOK(20500, testSampleRate, Options{Baud: 100})
+
+	signal := m.Modulate(payload)
+	got, err := m.Demodulate(signal)
+	if err != nil {
+		t.Fatalf("Demodulate returned error: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("round trip mismatch: got %x, want %x", got, payload)
+	}
+}
+
+func TestChirpRoundTrip(t *testing.T) {
+	payload := randomPayload(3, 16)
+	m := NewChirp(19000, 21000, testSampleRate, Options{Baud: 100})
+
+	signal := m.Modulate(payload)
+	got, err := m.Demodulate(signal)
+	if err != nil {
+		t.Fatalf("Demodulate returned error: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("round trip mismatch: got %x, want %x", got, payload)
+	}
+}
+
+func TestRoundTripWithRaisedCosineShape(t *testing.T) {
+	payload := randomPayload(4, 8)
+	opts := Options{Baud: 100, Shape: ShapeRaisedCosine}
+
+	for name, m := range map[string]interface {
+		Modulator
+		Demodulator
+	}{
+		"fsk": NewFSK(20000, 21000, testSampleRate, opts),
+		"ook": NewOOK(20500, testSampleRate, opts),
+	} {
+		signal := m.Modulate(payload)
+		got, err := m.Demodulate(signal)
+		if err != nil {
+			t.Fatalf("%s: Demodulate returned error: %v", name, err)
+		}
+		if !bytes.Equal(got, payload) {
+			t.Fatalf("%s: round trip mismatch: got %x, want %x", name, got, payload)
+		}
+	}
+}
+
+func TestEmptyPayloadRoundTrip(t *testing.T) {
+	m := NewFSK(20000, 21000, testSampleRate, Options{Baud: 100})
+
+	signal := m.Modulate(nil)
+	got, err := m.Demodulate(signal)
+	if err != nil {
+		t.Fatalf("Demodulate returned error: %v", err)
+	}
+	if len(got) != 0 {
+		t.Fatalf("expected empty payload, got %x", got)
+	}
+}
+
+func TestDemodulateCorruptedFrame(t *testing.T) {
+	payload := randomPayload(5, 16)
+	m := NewFSK(20000, 21000, testSampleRate, Options{Baud: 100})
+
+	signal := m.Modulate(payload)
+	symbolLen := m.Opts.symbolLen(m.SampleRate)
+	// Corrupt a handful of symbols partway through the payload by zeroing
+	// them out, which should flip enough bits to fail the CRC check.
+	start := symbolLen * headerBytes * 8
+	for i := start; i < start+symbolLen*4 && i < len(signal); i++ {
+		signal[i] = 0
+	}
+
+	if _, err := m.Demodulate(signal); err == nil {
+		t.Fatal("expected error demodulating a corrupted signal, got nil")
+	}
+}
+
+func TestDemodulateWithLeadingSilenceAndOffset(t *testing.T) {
+	// Real captured audio isn't sample-aligned to a symbol boundary the way
+	// Modulate's direct output is: it carries arbitrary leading silence (or
+	// whatever else was playing before the frame started). Demodulate must
+	// use the preamble to find the true frame start rather than assuming
+	// samples[0] is the first symbol.
+	payload := randomPayload(6, 4)
+	opts := Options{Baud: 1000}
+
+	for name, m := range map[string]interface {
+		Modulator
+		Demodulator
+	}{
+		"fsk":   NewFSK(20000, 21000, testSampleRate, opts),
+		"ook":   NewOOK(20500, testSampleRate, opts),
+		"chirp": NewChirp(19000, 21000, testSampleRate, opts),
+	} {
+		frame := m.Modulate(payload)
+		signal := append(make([]float64, 37), frame...)
+
+		got, err := m.Demodulate(signal)
+		if err != nil {
+			t.Fatalf("%s: Demodulate returned error: %v", name, err)
+		}
+		if !bytes.Equal(got, payload) {
+			t.Fatalf("%s: round trip mismatch: got %x, want %x", name, got, payload)
+		}
+	}
+}
+
+func TestDemodulateWithSubstantialLeadingSilence(t *testing.T) {
+	// findFrameStart must stay fast even when the lead-in is large: a real
+	// capture at DefaultBaud can easily carry half a second or more of
+	// silence (or unrelated audio) before the frame begins. A search that
+	// scores every single-sample offset with a full preamble decode is
+	// O(len(samples)) in the expensive part and blows up well past a
+	// second on input like this; this test catches that regression by
+	// failing if Demodulate takes too long, not just if it's wrong.
+	payload := randomPayload(7, 4)
+	m := NewFSK(20000, 21000, testSampleRate, Options{}) // DefaultBaud
+
+	lead := make([]float64, testSampleRate/2) // 0.5s of silence
+	signal := append(lead, m.Modulate(payload)...)
+
+	start := time.Now()
+	got, err := m.Demodulate(signal)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Demodulate took %s with 0.5s of leading silence, want well under 1s", elapsed)
+	}
+	if err != nil {
+		t.Fatalf("Demodulate returned error: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Fatalf("round trip mismatch: got %x, want %x", got, payload)
+	}
+}
+
+func TestDemodulateTooShort(t *testing.T) {
+	m := NewFSK(20000, 21000, testSampleRate, Options{Baud: 100})
+	if _, err := m.Demodulate(make([]float64, 10)); err == nil {
+		t.Fatal("expected error demodulating a too-short signal, got nil")
+	}
+}
+
+func TestSymbolLenZeroBaud(t *testing.T) {
+	opts := Options{Baud: testSampleRate + 1}
+	if _, err := opts.validateSymbolLen(testSampleRate); err == nil {
+		t.Fatal("expected error for baud exceeding sample rate, got nil")
+	}
+}
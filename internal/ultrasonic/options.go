@@ -0,0 +1,90 @@
+package ultrasonic
+
+import (
+	"math"
+	"time"
+)
+
+// toneOptions holds the resolved configuration for GenerateTone. The zero
+// value is not directly usable; defaultToneOptions fills in sensible
+// defaults before any Option is applied.
+type toneOptions struct {
+	sampleRate  int
+	duration    time.Duration
+	samples     int
+	haveSamples bool
+	amplitude   float64
+	phase       float64
+}
+
+func defaultToneOptions() toneOptions {
+	return toneOptions{sampleRate: SampleRate, amplitude: 1.0}
+}
+
+// Option configures GenerateTone. Options are applied in order, but
+// WithDuration and WithSamples both resolve against the final sample rate,
+// so which one is passed last doesn't matter.
+type Option func(*toneOptions)
+
+// WithSampleRate overrides the sample rate used to render the tone and to
+// resolve WithDuration into a sample count. Defaults to SampleRate.
+func WithSampleRate(rate int) Option {
+	return func(o *toneOptions) { o.sampleRate = rate }
+}
+
+// WithDuration sets how long the tone lasts, to sub-second precision. It is
+// overridden by a later WithSamples call, and overrides an earlier one.
+func WithDuration(d time.Duration) Option {
+	return func(o *toneOptions) {
+		o.duration = d
+		o.haveSamples = false
+	}
+}
+
+// WithSamples sets the tone's length directly as a sample count, for
+// callers that need an exact number of samples rather than a duration. It
+// is overridden by a later WithDuration call, and overrides an earlier one.
+func WithSamples(n int) Option {
+	return func(o *toneOptions) {
+		o.samples = n
+		o.haveSamples = true
+	}
+}
+
+// WithAmplitude sets the tone's peak amplitude. Defaults to 1.0.
+func WithAmplitude(amplitude float64) Option {
+	return func(o *toneOptions) { o.amplitude = amplitude }
+}
+
+// WithPhase sets the tone's starting phase, in radians. Defaults to 0;
+// pass math.Pi/2 for a cosine.
+func WithPhase(phase float64) Option {
+	return func(o *toneOptions) { o.phase = phase }
+}
+
+// GenerateTone generates a single sine tone at freq, configured by opts. It
+// is the configurable counterpart to GenerateSineWave, which only accepts
+// an integer number of seconds at full amplitude and zero phase.
+func GenerateTone(freq float64, opts ...Option) []float64 {
+	o := defaultToneOptions()
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	numSamples := o.samples
+	if !o.haveSamples {
+		numSamples = int(o.duration.Seconds() * float64(o.sampleRate))
+	}
+	if numSamples <= 0 {
+		return []float64{}
+	}
+
+	data := make([]float64, numSamples)
+	step := 2 * math.Pi * freq / float64(o.sampleRate)
+	phase := o.phase
+	for i := range data {
+		data[i] = o.amplitude * math.Sin(phase)
+		phase = wrapPhase(phase + step)
+	}
+	return data
+}
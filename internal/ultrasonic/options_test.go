@@ -0,0 +1,84 @@
+package ultrasonic
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestGenerateToneDuration(t *testing.T) {
+	data := GenerateTone(26000.0, WithDuration(500*time.Millisecond))
+
+	wantLen := SampleRate / 2
+	if len(data) != wantLen {
+		t.Errorf("expected %d samples for 500ms, got %d", wantLen, len(data))
+	}
+}
+
+func TestGenerateToneSamples(t *testing.T) {
+	data := GenerateTone(25000.0, WithSamples(4410))
+
+	if len(data) != 4410 {
+		t.Errorf("expected 4410 samples, got %d", len(data))
+	}
+}
+
+func TestGenerateToneSamplesOverridesDuration(t *testing.T) {
+	data := GenerateTone(25000.0, WithDuration(2*time.Second), WithSamples(100))
+	if len(data) != 100 {
+		t.Errorf("expected WithSamples to win when applied after WithDuration, got %d samples", len(data))
+	}
+
+	data = GenerateTone(25000.0, WithSamples(100), WithDuration(2*time.Second))
+	if len(data) != SampleRate*2 {
+		t.Errorf("expected WithDuration to win when applied after WithSamples, got %d samples", len(data))
+	}
+}
+
+func TestGenerateToneAmplitude(t *testing.T) {
+	const amplitude = 0.5
+	data := GenerateTone(25000.0, WithSamples(SampleRate), WithAmplitude(amplitude))
+
+	for i, val := range data {
+		if val < -amplitude-1e-9 || val > amplitude+1e-9 {
+			t.Fatalf("sample %d out of range [-%.1f, %.1f]: %f", i, amplitude, amplitude, val)
+		}
+	}
+}
+
+func TestGenerateTonePhase(t *testing.T) {
+	data := GenerateTone(1000.0, WithSamples(1), WithPhase(math.Pi/2))
+
+	if math.Abs(data[0]-1.0) > 1e-9 {
+		t.Errorf("expected first sample of a pi/2 phase tone to be 1.0 (cosine start), got %f", data[0])
+	}
+}
+
+func TestGenerateToneSampleRate(t *testing.T) {
+	data := GenerateTone(1000.0, WithDuration(time.Second), WithSampleRate(8000))
+
+	if len(data) != 8000 {
+		t.Errorf("expected 8000 samples for 1 second at an 8kHz sample rate, got %d", len(data))
+	}
+}
+
+func TestGenerateToneNoLength(t *testing.T) {
+	data := GenerateTone(25000.0)
+	if len(data) != 0 {
+		t.Errorf("expected no samples without WithDuration or WithSamples, got %d", len(data))
+	}
+}
+
+func TestGenerateSineWaveMatchesGenerateTone(t *testing.T) {
+	want := GenerateTone(25000.0, WithDuration(time.Second))
+	got := GenerateSineWave(25000.0, 1)
+
+	if len(want) != len(got) {
+		t.Fatalf("length mismatch: %d vs %d", len(want), len(got))
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			t.Fatalf("sample %d mismatch: %f vs %f", i, want[i], got[i])
+		}
+	}
+}
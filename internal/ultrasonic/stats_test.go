@@ -0,0 +1,171 @@
+package ultrasonic
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+// meanStddev returns the sample mean and (population) standard deviation of
+// samples.
+func meanStddev(samples []float64) (mean, stddev float64) {
+	if len(samples) == 0 {
+		return 0, 0
+	}
+
+	for _, v := range samples {
+		mean += v
+	}
+	mean /= float64(len(samples))
+
+	var variance float64
+	for _, v := range samples {
+		d := v - mean
+		variance += d * d
+	}
+	variance /= float64(len(samples))
+
+	return mean, math.Sqrt(variance)
+}
+
+// nearEqual reports whether a and b are close enough, either in absolute
+// terms (within closeEnough) or relative terms (within maxError of the
+// larger magnitude) — the same two-tier check math/rand's test suite uses
+// to tolerate both small values and floating-point noise.
+func nearEqual(a, b, closeEnough, maxError float64) bool {
+	absDiff := math.Abs(a - b)
+	if absDiff < closeEnough {
+		return true
+	}
+	return absDiff/math.Max(math.Abs(a), math.Abs(b)) < maxError
+}
+
+// goertzelPower returns the power of samples at targetFreq, via the
+// Goertzel algorithm. Unlike an FFT bin, targetFreq need not land exactly
+// on a bin boundary, which is what makes it useful as a peak-search
+// frequency detector over a short window.
+func goertzelPower(samples []float64, targetFreq float64, sampleRate int) float64 {
+	n := len(samples)
+	if n == 0 {
+		return 0
+	}
+
+	omega := 2 * math.Pi * targetFreq / float64(sampleRate)
+	coeff := 2 * math.Cos(omega)
+
+	var s0, s1, s2 float64
+	for _, x := range samples {
+		s0 = x + coeff*s1 - s2
+		s2 = s1
+		s1 = s0
+	}
+	return s1*s1 + s2*s2 - coeff*s1*s2
+}
+
+// estimateFrequency scans [loHz, hiHz] in stepHz increments and returns the
+// frequency with the strongest Goertzel power in window — a peak-search
+// frequency detector that works at any frequency in range, not just ones
+// low enough to count zero crossings accurately.
+func estimateFrequency(window []float64, sampleRate int, loHz, hiHz, stepHz float64) float64 {
+	bestFreq := loHz
+	bestPower := -1.0
+	for f := loHz; f <= hiHz; f += stepHz {
+		p := goertzelPower(window, f, sampleRate)
+		if p > bestPower {
+			bestPower = p
+			bestFreq = f
+		}
+	}
+	return bestFreq
+}
+
+func TestGenerateSineWave_Distribution(t *testing.T) {
+	for _, freq := range []float64{1000.0, 20000.0, 25000.0, 29999.0} {
+		data := GenerateSineWave(freq, 1)
+
+		mean, stddev := meanStddev(data)
+		if !nearEqual(mean, 0, 1e-9, 0.01) {
+			t.Errorf("freq %.1f: expected mean ~0, got %.6f", freq, mean)
+		}
+
+		wantRMS := 1 / math.Sqrt2
+		if !nearEqual(stddev, wantRMS, 1e-9, 0.01) {
+			t.Errorf("freq %.1f: expected RMS ~%.6f, got %.6f", freq, wantRMS, stddev)
+		}
+	}
+}
+
+func TestGenerateSineWave_FrequencyGoertzel(t *testing.T) {
+	// The old zero-crossing estimate only worked below ~1kHz; Goertzel
+	// peak search is accurate across the whole ultrasonic band.
+	for _, freq := range []float64{20000.0, 25000.0, 29999.0} {
+		data := GenerateSineWave(freq, 1)
+		window := data[:4410] // a short-time window is enough to pin down a steady tone
+
+		got := estimateFrequency(window, SampleRate, freq-50, freq+50, 0.5)
+		if tolerance := freq * 0.001; math.Abs(got-freq) > tolerance {
+			t.Errorf("freq %.1f: estimated %.2f Hz, want within %.2f Hz", freq, got, tolerance)
+		}
+	}
+}
+
+func TestGenerateSweep_Endpoints(t *testing.T) {
+	const startFreq, endFreq = 24000.0, 26000.0
+	data := GenerateSweep(startFreq, endFreq, 1)
+
+	// A window short enough that the sweep's own frequency drift across it
+	// is well inside the 0.1% tolerance we're checking for.
+	const windowLen = 256
+
+	startWindow := data[:windowLen]
+	gotStart := estimateFrequency(startWindow, SampleRate, startFreq-200, startFreq+200, 0.5)
+	if tolerance := startFreq * 0.001; math.Abs(gotStart-startFreq) > tolerance {
+		t.Errorf("sweep start: estimated %.2f Hz, want within %.2f Hz of %.1f", gotStart, tolerance, startFreq)
+	}
+
+	endWindow := data[len(data)-windowLen:]
+	gotEnd := estimateFrequency(endWindow, SampleRate, endFreq-200, endFreq+200, 0.5)
+	if tolerance := endFreq * 0.001; math.Abs(gotEnd-endFreq) > tolerance {
+		t.Errorf("sweep end: estimated %.2f Hz, want within %.2f Hz of %.1f", gotEnd, tolerance, endFreq)
+	}
+}
+
+func TestGenerateMultiTone_HarmonicPower(t *testing.T) {
+	const baseFreq = 25000.0
+	const harmonics = 3
+	data := GenerateMultiTone(baseFreq, 1, harmonics)
+
+	n := nextPowerOfTwo(len(data))
+	spectrum := make([]complex128, n)
+	for i, v := range data {
+		spectrum[i] = complex(v, 0)
+	}
+	fft(spectrum, false)
+
+	power := make([]float64, n)
+	var totalPower float64
+	for i, c := range spectrum {
+		power[i] = cmplx.Abs(c) * cmplx.Abs(c)
+		totalPower += power[i]
+	}
+
+	// At least 90% of the spectrum's energy should sit within the
+	// harmonics' own bins (one bin either side, to tolerate spectral
+	// leakage from the signal not being an exact integer number of cycles).
+	var harmonicPower float64
+	binHz := float64(SampleRate) / float64(n)
+	for h := 0; h < harmonics; h++ {
+		target := baseFreq + float64(h)*100
+		bin := int(math.Round(target / binHz))
+		mirror := n - bin
+		for _, b := range []int{bin - 1, bin, bin + 1, mirror - 1, mirror, mirror + 1} {
+			if b >= 0 && b < n {
+				harmonicPower += power[b]
+			}
+		}
+	}
+
+	if frac := harmonicPower / totalPower; frac < 0.9 {
+		t.Errorf("expected >=90%% of spectral power at the %d harmonic bins, got %.1f%%", harmonics, frac*100)
+	}
+}
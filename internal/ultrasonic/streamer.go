@@ -0,0 +1,185 @@
+package ultrasonic
+
+import "math"
+
+// Streamer produces audio samples incrementally, carrying oscillator phase
+// across calls so callers can render arbitrarily long (or open-ended)
+// signals into small, reusable buffers instead of allocating one slice per
+// duration.
+type Streamer interface {
+	// Stream fills buf with the next len(buf) samples, returning the number
+	// of samples written and false once the streamer has no more samples to
+	// produce (finite streamers like NewSweep); infinite streamers like
+	// NewSineTone always report n == len(buf) and ok == true.
+	Stream(buf []float64) (n int, ok bool)
+}
+
+// wrapPhase keeps an accumulated phase within [0, 2π) so it never grows
+// without bound, which is what lets a streamer run for minutes at a
+// near-ultrasonic frequency without losing precision to floating-point
+// rounding.
+func wrapPhase(phase float64) float64 {
+	if phase >= 2*math.Pi {
+		return phase - 2*math.Pi
+	}
+	if phase < 0 {
+		return phase + 2*math.Pi
+	}
+	return phase
+}
+
+// sineStreamer is an infinite constant-frequency oscillator.
+type sineStreamer struct {
+	freq  float64
+	phase float64
+}
+
+// NewSineTone returns a Streamer producing a continuous sine wave at freq.
+func NewSineTone(freq float64) Streamer {
+	return &sineStreamer{freq: freq}
+}
+
+func (s *sineStreamer) Stream(buf []float64) (int, bool) {
+	step := 2 * math.Pi * s.freq / float64(SampleRate)
+	for i := range buf {
+		buf[i] = math.Sin(s.phase)
+		s.phase = wrapPhase(s.phase + step)
+	}
+	return len(buf), true
+}
+
+// sweepStreamer is a finite linear frequency sweep from startFreq to
+// endFreq over totalSamples samples.
+type sweepStreamer struct {
+	startFreq, endFreq float64
+	totalSamples       int
+	pos                int
+	phase              float64
+}
+
+// NewSweep returns a Streamer sweeping linearly from startFreq to endFreq
+// over durationSec seconds; Stream reports ok == false once that many
+// samples have been produced.
+func NewSweep(startFreq, endFreq float64, durationSec int) Streamer {
+	total := SampleRate * durationSec
+	if total < 0 {
+		total = 0
+	}
+	return &sweepStreamer{startFreq: startFreq, endFreq: endFreq, totalSamples: total}
+}
+
+func (s *sweepStreamer) Stream(buf []float64) (int, bool) {
+	freqRange := s.endFreq - s.startFreq
+	n := 0
+	for n < len(buf) {
+		if s.pos >= s.totalSamples {
+			return n, false
+		}
+
+		var progress float64
+		if s.totalSamples > 1 {
+			progress = float64(s.pos) / float64(s.totalSamples-1)
+		}
+		currentFreq := s.startFreq + freqRange*progress
+
+		buf[n] = math.Sin(s.phase)
+		s.phase = wrapPhase(s.phase + 2*math.Pi*currentFreq/float64(SampleRate))
+		s.pos++
+		n++
+	}
+	return n, true
+}
+
+// repeatingSweepStreamer is a finite linear sweep from startFreq to endFreq
+// that repeats every periodSamples samples, for totalSamples samples in
+// total, with phase accumulated continuously across period boundaries so
+// the waveform has no discontinuity where one repetition ends and the next
+// begins.
+type repeatingSweepStreamer struct {
+	startFreq, endFreq float64
+	periodSamples      int
+	totalSamples       int
+	pos                int
+	phase              float64
+}
+
+// NewRepeatingSweep returns a Streamer sweeping linearly from startFreq to
+// endFreq every periodMs milliseconds, repeating for durationSec seconds;
+// Stream reports ok == false once that many samples have been produced.
+func NewRepeatingSweep(startFreq, endFreq float64, periodMs, durationSec int) Streamer {
+	period := SampleRate * periodMs / 1000
+	if period < 0 {
+		period = 0
+	}
+	total := SampleRate * durationSec
+	if total < 0 {
+		total = 0
+	}
+	return &repeatingSweepStreamer{startFreq: startFreq, endFreq: endFreq, periodSamples: period, totalSamples: total}
+}
+
+func (s *repeatingSweepStreamer) Stream(buf []float64) (int, bool) {
+	if s.periodSamples <= 0 {
+		return 0, false
+	}
+
+	freqRange := s.endFreq - s.startFreq
+	n := 0
+	for n < len(buf) {
+		if s.pos >= s.totalSamples {
+			return n, false
+		}
+
+		progress := float64(s.pos%s.periodSamples) / float64(s.periodSamples)
+		currentFreq := s.startFreq + freqRange*progress
+
+		buf[n] = math.Sin(s.phase)
+		s.phase = wrapPhase(s.phase + 2*math.Pi*currentFreq/float64(SampleRate))
+		s.pos++
+		n++
+	}
+	return n, true
+}
+
+// multiToneStreamer is an infinite mix of harmonics harmonics, each a pure
+// tone 100Hz apart starting at baseFreq, each with its own phase so they
+// stay mutually coherent across Stream calls.
+type multiToneStreamer struct {
+	baseFreq  float64
+	harmonics int
+	phases    []float64
+}
+
+// NewMultiTone returns a Streamer mixing harmonics tones spaced 100Hz apart
+// starting at baseFreq, dividing each tone's amplitude by harmonics so the
+// mix stays within [-1, 1] even in the worst case where every tone peaks
+// in phase.
+//
+// This is a different, more conservative normalization strategy than
+// GenerateMultiTone/MixTones' true-peak headroom normalization: a
+// streaming mix can't measure the true peak of a signal it hasn't produced
+// yet and renormalize after the fact the way a whole-buffer generator can,
+// so for the same (baseFreq, harmonics) this streamer is quieter than
+// GenerateMultiTone. Treat the two as similar in shape, not interchangeable
+// in loudness.
+func NewMultiTone(baseFreq float64, harmonics int) Streamer {
+	return &multiToneStreamer{baseFreq: baseFreq, harmonics: harmonics, phases: make([]float64, harmonics)}
+}
+
+func (m *multiToneStreamer) Stream(buf []float64) (int, bool) {
+	if m.harmonics <= 0 {
+		return 0, false
+	}
+
+	amplitude := 1.0 / float64(m.harmonics)
+	for i := range buf {
+		var sample float64
+		for h := 0; h < m.harmonics; h++ {
+			freq := m.baseFreq + float64(h)*100 // Spread frequencies 100Hz apart
+			sample += amplitude * math.Sin(m.phases[h])
+			m.phases[h] = wrapPhase(m.phases[h] + 2*math.Pi*freq/float64(SampleRate))
+		}
+		buf[i] = sample
+	}
+	return len(buf), true
+}
@@ -0,0 +1,98 @@
+package ultrasonic
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSineStreamerPhaseContinuity(t *testing.T) {
+	freq := 1000.0
+
+	// One streamer pulling in two calls...
+	s := NewSineTone(freq)
+	first := make([]float64, 100)
+	second := make([]float64, 100)
+	s.Stream(first)
+	s.Stream(second)
+
+	// ...should match a single streamer pulling the concatenated length in
+	// one call, since phase must carry across Stream calls.
+	whole := make([]float64, 200)
+	NewSineTone(freq).Stream(whole)
+
+	for i := 0; i < 100; i++ {
+		if math.Abs(first[i]-whole[i]) > 1e-9 {
+			t.Fatalf("sample %d mismatch in first half: %.9f vs %.9f", i, first[i], whole[i])
+		}
+	}
+	for i := 0; i < 100; i++ {
+		if math.Abs(second[i]-whole[100+i]) > 1e-9 {
+			t.Fatalf("sample %d mismatch in second half: %.9f vs %.9f", i, second[i], whole[100+i])
+		}
+	}
+}
+
+func TestSineStreamerMatchesGenerateSineWave(t *testing.T) {
+	want := GenerateSineWave(25000.0, 1)
+
+	got := make([]float64, len(want))
+	NewSineTone(25000.0).Stream(got)
+
+	for i := range want {
+		if math.Abs(want[i]-got[i]) > 1e-9 {
+			t.Fatalf("sample %d mismatch: %.9f vs %.9f", i, want[i], got[i])
+		}
+	}
+}
+
+func TestSweepStreamerFinite(t *testing.T) {
+	s := NewSweep(24000.0, 26000.0, 1)
+	buf := make([]float64, SampleRate)
+
+	n, ok := s.Stream(buf)
+	if n != SampleRate || !ok {
+		t.Fatalf("expected %d samples with ok=true, got %d ok=%v", SampleRate, n, ok)
+	}
+
+	n, ok = s.Stream(buf[:10])
+	if n != 0 || ok {
+		t.Fatalf("expected no more samples after totalSamples exhausted, got n=%d ok=%v", n, ok)
+	}
+}
+
+func TestMultiToneStreamerStaysInRange(t *testing.T) {
+	s := NewMultiTone(25000.0, 5)
+	buf := make([]float64, SampleRate)
+	s.Stream(buf)
+
+	for i, v := range buf {
+		if v < -1.0 || v > 1.0 {
+			t.Fatalf("sample %d out of range [-1, 1]: %f", i, v)
+		}
+	}
+}
+
+func TestMultiToneStreamerZeroHarmonics(t *testing.T) {
+	s := NewMultiTone(25000.0, 0)
+	n, ok := s.Stream(make([]float64, 10))
+	if n != 0 || ok {
+		t.Fatalf("expected n=0 ok=false for zero harmonics, got n=%d ok=%v", n, ok)
+	}
+}
+
+func BenchmarkSineStreamer(b *testing.B) {
+	s := NewSineTone(25000.0)
+	buf := make([]float64, SampleRate)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		s.Stream(buf)
+	}
+}
+
+func BenchmarkGenerateSineWave_VsStreamer(b *testing.B) {
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		GenerateSineWave(25000.0, 1)
+	}
+}
@@ -0,0 +1,235 @@
+// Package wav reads and writes the raw []float64 signals produced by the
+// ultrasonic package as standard RIFF/WAV files, so generated carriers can
+// be captured for offline analysis (Audacity, SoX) and recordings can be
+// loaded back in for future decoding pipelines.
+package wav
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/nomihq/camouflage/internal/ultrasonic"
+)
+
+// Options configures how samples are encoded to a WAV file.
+type Options struct {
+	SampleRate    int
+	Channels      int
+	BitsPerSample int // 16 for PCM, 32 for IEEE float
+}
+
+// DefaultOptions mirrors what the ultrasonic package generates: mono,
+// 16-bit PCM, at ultrasonic.SampleRate. It's a function rather than a
+// package-level value because ultrasonic.SampleRate can be overridden
+// after init (by --sample-rate) and callers expect that override to be
+// reflected in files they write afterward.
+func DefaultOptions() Options {
+	return Options{
+		SampleRate:    ultrasonic.SampleRate,
+		Channels:      1,
+		BitsPerSample: 16,
+	}
+}
+
+// Header describes a WAV file's format, as returned by Read and ReadFile.
+type Header struct {
+	SampleRate    int
+	Channels      int
+	BitsPerSample int
+}
+
+// Duration returns how long numFrames interleaved sample frames play for
+// under this header's sample rate.
+func (h Header) Duration(numFrames int) time.Duration {
+	if h.SampleRate <= 0 {
+		return 0
+	}
+	return time.Duration(numFrames) * time.Second / time.Duration(h.SampleRate)
+}
+
+// WriteFile writes samples to path using DefaultOptions.
+func WriteFile(path string, samples []float64) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("wav: creating %s: %w", path, err)
+	}
+	defer f.Close()
+	return Write(f, samples, DefaultOptions())
+}
+
+// Write encodes samples (each expected to be within [-1, 1], and clamped if
+// not) as a RIFF/WAVE stream to w, using opts to choose PCM vs. IEEE float
+// encoding, channel count, and sample rate.
+func Write(w io.Writer, samples []float64, opts Options) error {
+	if opts.SampleRate <= 0 {
+		return fmt.Errorf("wav: sample rate must be positive, got %d", opts.SampleRate)
+	}
+	if opts.Channels <= 0 {
+		return fmt.Errorf("wav: channels must be positive, got %d", opts.Channels)
+	}
+
+	var audioFormat uint16
+	switch opts.BitsPerSample {
+	case 16:
+		audioFormat = 1 // PCM
+	case 32:
+		audioFormat = 3 // IEEE float
+	default:
+		return fmt.Errorf("wav: unsupported bits per sample %d (want 16 or 32)", opts.BitsPerSample)
+	}
+
+	bytesPerSample := opts.BitsPerSample / 8
+	blockAlign := opts.Channels * bytesPerSample
+	byteRate := opts.SampleRate * blockAlign
+	dataSize := len(samples) * bytesPerSample
+
+	if err := writeHeader(w, opts, audioFormat, blockAlign, byteRate, dataSize); err != nil {
+		return err
+	}
+
+	buf := make([]byte, bytesPerSample)
+	for _, s := range samples {
+		if s > 1 {
+			s = 1
+		} else if s < -1 {
+			s = -1
+		}
+
+		switch opts.BitsPerSample {
+		case 16:
+			binary.LittleEndian.PutUint16(buf, uint16(int16(s*math.MaxInt16)))
+		case 32:
+			binary.LittleEndian.PutUint32(buf, math.Float32bits(float32(s)))
+		}
+		if _, err := w.Write(buf); err != nil {
+			return fmt.Errorf("wav: writing sample data: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func writeHeader(w io.Writer, opts Options, audioFormat uint16, blockAlign, byteRate, dataSize int) error {
+	var hdr [44]byte
+	copy(hdr[0:4], "RIFF")
+	binary.LittleEndian.PutUint32(hdr[4:8], uint32(36+dataSize))
+	copy(hdr[8:12], "WAVE")
+	copy(hdr[12:16], "fmt ")
+	binary.LittleEndian.PutUint32(hdr[16:20], 16)
+	binary.LittleEndian.PutUint16(hdr[20:22], audioFormat)
+	binary.LittleEndian.PutUint16(hdr[22:24], uint16(opts.Channels))
+	binary.LittleEndian.PutUint32(hdr[24:28], uint32(opts.SampleRate))
+	binary.LittleEndian.PutUint32(hdr[28:32], uint32(byteRate))
+	binary.LittleEndian.PutUint16(hdr[32:34], uint16(blockAlign))
+	binary.LittleEndian.PutUint16(hdr[34:36], uint16(opts.BitsPerSample))
+	copy(hdr[36:40], "data")
+	binary.LittleEndian.PutUint32(hdr[40:44], uint32(dataSize))
+	_, err := w.Write(hdr[:])
+	if err != nil {
+		return fmt.Errorf("wav: writing header: %w", err)
+	}
+	return nil
+}
+
+// ReadFile reads the WAV file at path back into normalized samples.
+func ReadFile(path string) ([]float64, Header, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, Header{}, fmt.Errorf("wav: opening %s: %w", path, err)
+	}
+	defer f.Close()
+	return Read(f)
+}
+
+// Read parses a RIFF/WAVE stream, returning its samples normalized to
+// [-1, 1] (interleaved, if Header.Channels > 1) along with its header.
+func Read(r io.Reader) ([]float64, Header, error) {
+	var riffHdr [12]byte
+	if _, err := io.ReadFull(r, riffHdr[:]); err != nil {
+		return nil, Header{}, fmt.Errorf("wav: reading RIFF header: %w", err)
+	}
+	if string(riffHdr[0:4]) != "RIFF" || string(riffHdr[8:12]) != "WAVE" {
+		return nil, Header{}, fmt.Errorf("wav: not a RIFF/WAVE stream")
+	}
+
+	var header Header
+	var audioFormat uint16
+	var samples []float64
+	haveFmt := false
+
+	for {
+		var chunkHdr [8]byte
+		if _, err := io.ReadFull(r, chunkHdr[:]); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, Header{}, fmt.Errorf("wav: reading chunk header: %w", err)
+		}
+		chunkID := string(chunkHdr[0:4])
+		chunkSize := binary.LittleEndian.Uint32(chunkHdr[4:8])
+
+		body := make([]byte, chunkSize)
+		if _, err := io.ReadFull(r, body); err != nil {
+			return nil, Header{}, fmt.Errorf("wav: reading %q chunk: %w", chunkID, err)
+		}
+		// Chunks are word-aligned; a chunk with odd size is followed by a
+		// pad byte that isn't counted in chunkSize.
+		if chunkSize%2 == 1 {
+			if _, err := io.CopyN(io.Discard, r, 1); err != nil && err != io.EOF {
+				return nil, Header{}, fmt.Errorf("wav: reading chunk padding: %w", err)
+			}
+		}
+
+		switch chunkID {
+		case "fmt ":
+			if len(body) < 16 {
+				return nil, Header{}, fmt.Errorf("wav: fmt chunk too short (%d bytes)", len(body))
+			}
+			audioFormat = binary.LittleEndian.Uint16(body[0:2])
+			header.Channels = int(binary.LittleEndian.Uint16(body[2:4]))
+			header.SampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+			header.BitsPerSample = int(binary.LittleEndian.Uint16(body[14:16]))
+			haveFmt = true
+		case "data":
+			if !haveFmt {
+				return nil, Header{}, fmt.Errorf("wav: data chunk appeared before fmt chunk")
+			}
+			decoded, err := decodeSamples(body, header, audioFormat)
+			if err != nil {
+				return nil, Header{}, err
+			}
+			samples = decoded
+		}
+	}
+
+	if !haveFmt {
+		return nil, Header{}, fmt.Errorf("wav: missing fmt chunk")
+	}
+	return samples, header, nil
+}
+
+func decodeSamples(data []byte, header Header, audioFormat uint16) ([]float64, error) {
+	bytesPerSample := header.BitsPerSample / 8
+	if bytesPerSample == 0 {
+		return nil, fmt.Errorf("wav: invalid bits per sample %d", header.BitsPerSample)
+	}
+
+	n := len(data) / bytesPerSample
+	samples := make([]float64, n)
+	for i := 0; i < n; i++ {
+		chunk := data[i*bytesPerSample : (i+1)*bytesPerSample]
+		switch {
+		case audioFormat == 1 && header.BitsPerSample == 16:
+			samples[i] = float64(int16(binary.LittleEndian.Uint16(chunk))) / math.MaxInt16
+		case audioFormat == 3 && header.BitsPerSample == 32:
+			samples[i] = float64(math.Float32frombits(binary.LittleEndian.Uint32(chunk)))
+		default:
+			return nil, fmt.Errorf("wav: unsupported encoding (audioFormat=%d, bitsPerSample=%d)", audioFormat, header.BitsPerSample)
+		}
+	}
+	return samples, nil
+}
@@ -0,0 +1,139 @@
+package wav
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/nomihq/camouflage/internal/ultrasonic"
+)
+
+func TestWriteReadRoundTrip16BitPCM(t *testing.T) {
+	samples := ultrasonic.GenerateSineWave(25000.0, 1)
+
+	var buf bytes.Buffer
+	if err := Write(&buf, samples, DefaultOptions()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	got, header, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	if header.SampleRate != ultrasonic.SampleRate {
+		t.Errorf("SampleRate = %d, want %d", header.SampleRate, ultrasonic.SampleRate)
+	}
+	if header.Channels != 1 {
+		t.Errorf("Channels = %d, want 1", header.Channels)
+	}
+	if header.BitsPerSample != 16 {
+		t.Errorf("BitsPerSample = %d, want 16", header.BitsPerSample)
+	}
+	if len(got) != len(samples) {
+		t.Fatalf("got %d samples, want %d", len(got), len(samples))
+	}
+
+	// 16-bit PCM quantizes to 1/32767 of full scale.
+	const quantizationError = 1.0 / math.MaxInt16
+	for i := range samples {
+		if math.Abs(got[i]-samples[i]) > quantizationError {
+			t.Fatalf("sample %d: got %.6f, want %.6f (diff exceeds quantization error)", i, got[i], samples[i])
+		}
+	}
+}
+
+func TestWriteReadRoundTrip32BitFloat(t *testing.T) {
+	samples := ultrasonic.GenerateSweep(24000.0, 26000.0, 1)
+	opts := Options{SampleRate: ultrasonic.SampleRate, Channels: 1, BitsPerSample: 32}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, samples, opts); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	got, header, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+
+	if header.BitsPerSample != 32 {
+		t.Errorf("BitsPerSample = %d, want 32", header.BitsPerSample)
+	}
+	if len(got) != len(samples) {
+		t.Fatalf("got %d samples, want %d", len(got), len(samples))
+	}
+
+	const float32Error = 1e-6
+	for i := range samples {
+		if math.Abs(got[i]-samples[i]) > float32Error {
+			t.Fatalf("sample %d: got %.9f, want %.9f", i, got[i], samples[i])
+		}
+	}
+}
+
+func TestWriteFileReadFile(t *testing.T) {
+	samples := ultrasonic.GenerateMultiTone(25000.0, 1, 3)
+	path := t.TempDir() + "/tone.wav"
+
+	if err := WriteFile(path, samples); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	got, header, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if len(got) != len(samples) {
+		t.Fatalf("got %d samples, want %d", len(got), len(samples))
+	}
+	if header.Duration(len(got)) <= 0 {
+		t.Errorf("expected positive duration, got %v", header.Duration(len(got)))
+	}
+}
+
+func TestWriteInvalidOptions(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+	}{
+		{"zero sample rate", Options{SampleRate: 0, Channels: 1, BitsPerSample: 16}},
+		{"zero channels", Options{SampleRate: 44100, Channels: 0, BitsPerSample: 16}},
+		{"unsupported bit depth", Options{SampleRate: 44100, Channels: 1, BitsPerSample: 8}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, []float64{0}, tt.opts); err == nil {
+				t.Error("expected error, got none")
+			}
+		})
+	}
+}
+
+func TestReadRejectsNonWAV(t *testing.T) {
+	if _, _, err := Read(bytes.NewReader([]byte("not a wav file"))); err == nil {
+		t.Error("expected error reading non-WAV data, got none")
+	}
+}
+
+func TestWriteClampsOutOfRangeSamples(t *testing.T) {
+	samples := []float64{2.0, -2.0}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, samples, DefaultOptions()); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	got, _, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if math.Abs(got[0]-1.0) > 1e-4 {
+		t.Errorf("expected clamped sample near 1.0, got %.4f", got[0])
+	}
+	if math.Abs(got[1]+1.0) > 1e-4 {
+		t.Errorf("expected clamped sample near -1.0, got %.4f", got[1])
+	}
+}
@@ -0,0 +1,63 @@
+package vad
+
+import "math"
+
+// Speech energy is concentrated below these zero-crossing rates; frames
+// with a much higher ZCR tend to be hiss/fricative noise rather than voiced
+// speech.
+const (
+	minSpeechZCR = 0.005
+	maxSpeechZCR = 0.15
+)
+
+// EnergyDetector is a simple RMS-energy + zero-crossing-rate speech
+// detector: a frame is speech if its RMS is above threshold and its
+// zero-crossing rate falls within a speech-like band.
+type EnergyDetector struct {
+	sampleRate int
+	threshold  float64
+}
+
+// NewEnergyDetector creates an EnergyDetector. threshold is an RMS level in
+// [0, 1]; frames at or above it (and within the speech-like ZCR band) are
+// reported as speech.
+func NewEnergyDetector(sampleRate int, threshold float64) *EnergyDetector {
+	return &EnergyDetector{sampleRate: sampleRate, threshold: threshold}
+}
+
+// Detect reports whether frame looks like speech.
+func (e *EnergyDetector) Detect(frame []float32) bool {
+	if len(frame) == 0 {
+		return false
+	}
+
+	if rms(frame) < e.threshold {
+		return false
+	}
+
+	zcr := zeroCrossingRate(frame)
+	return zcr >= minSpeechZCR && zcr <= maxSpeechZCR
+}
+
+func rms(frame []float32) float64 {
+	var sumSq float64
+	for _, s := range frame {
+		sumSq += float64(s) * float64(s)
+	}
+	return math.Sqrt(sumSq / float64(len(frame)))
+}
+
+// zeroCrossingRate returns the fraction of adjacent sample pairs in frame
+// that cross zero.
+func zeroCrossingRate(frame []float32) float64 {
+	if len(frame) < 2 {
+		return 0
+	}
+	crossings := 0
+	for i := 1; i < len(frame); i++ {
+		if (frame[i-1] < 0) != (frame[i] < 0) {
+			crossings++
+		}
+	}
+	return float64(crossings) / float64(len(frame)-1)
+}
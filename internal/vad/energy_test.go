@@ -0,0 +1,47 @@
+package vad
+
+import (
+	"math"
+	"testing"
+)
+
+func sineFrame(freq float64, sampleRate, n int) []float32 {
+	frame := make([]float32, n)
+	for i := range frame {
+		frame[i] = float32(math.Sin(2 * math.Pi * freq * float64(i) / float64(sampleRate)))
+	}
+	return frame
+}
+
+func TestEnergyDetectorSilence(t *testing.T) {
+	d := NewEnergyDetector(44100, 0.05)
+	frame := make([]float32, 882) // 20ms of silence at 44.1kHz
+	if d.Detect(frame) {
+		t.Error("expected silence not to be detected as speech")
+	}
+}
+
+func TestEnergyDetectorVoiceLikeTone(t *testing.T) {
+	d := NewEnergyDetector(44100, 0.05)
+	frame := sineFrame(200.0, 44100, 882) // low-frequency tone, speech-like ZCR
+	if !d.Detect(frame) {
+		t.Error("expected a loud low-frequency tone to be detected as speech")
+	}
+}
+
+func TestEnergyDetectorHighFrequencyToneRejected(t *testing.T) {
+	d := NewEnergyDetector(44100, 0.05)
+	frame := sineFrame(10000.0, 44100, 882) // well outside the speech-like ZCR band
+	if d.Detect(frame) {
+		t.Error("expected a 10kHz tone not to be detected as speech")
+	}
+}
+
+func TestRMS(t *testing.T) {
+	frame := sineFrame(440.0, 44100, 44100)
+	got := rms(frame)
+	want := 1 / math.Sqrt2
+	if math.Abs(got-want) > 0.01 {
+		t.Errorf("rms() = %f, want ~%f", got, want)
+	}
+}
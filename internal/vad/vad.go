@@ -0,0 +1,43 @@
+// Package vad provides voice-activity detection over short frames of mono
+// audio, used to gate the ultrasonic carrier so it only plays while the user
+// is actually speaking.
+package vad
+
+import "fmt"
+
+// Backend selects which voice-activity-detection algorithm to use.
+type Backend string
+
+const (
+	// BackendOff disables gating entirely: Detect always reports speech.
+	BackendOff Backend = "off"
+	// BackendEnergy is a simple RMS-energy + zero-crossing-rate detector.
+	BackendEnergy Backend = "energy"
+	// BackendWebRTC wraps the WebRTC project's VAD (requires cgo).
+	BackendWebRTC Backend = "webrtc"
+)
+
+// Detector reports whether a frame of mono audio contains speech.
+type Detector interface {
+	Detect(frame []float32) bool
+}
+
+// New returns the Detector for the given backend.
+func New(backend Backend, sampleRate int, threshold float64) (Detector, error) {
+	switch backend {
+	case BackendOff, "":
+		return alwaysSpeaking{}, nil
+	case BackendEnergy:
+		return NewEnergyDetector(sampleRate, threshold), nil
+	case BackendWebRTC:
+		return newWebRTCDetector(sampleRate)
+	default:
+		return nil, fmt.Errorf("vad: unknown backend %q", backend)
+	}
+}
+
+// alwaysSpeaking is the Detector used for BackendOff: it never gates the
+// carrier off, matching the un-gated behavior before --vad existed.
+type alwaysSpeaking struct{}
+
+func (alwaysSpeaking) Detect(frame []float32) bool { return true }
@@ -0,0 +1,58 @@
+//go:build cgo
+// +build cgo
+
+package vad
+
+/*
+#cgo pkg-config: webrtcvad
+#include <webrtc_vad.h>
+*/
+import "C"
+
+import "fmt"
+
+// webrtcDetector wraps the WebRTC project's VAD, a GMM-based detector
+// commonly used in VoIP stacks (and by NoiseTorch-adjacent tools for speech
+// gating).
+type webrtcDetector struct {
+	inst       *C.VadInst
+	sampleRate int
+}
+
+func newWebRTCDetector(sampleRate int) (Detector, error) {
+	switch sampleRate {
+	case 8000, 16000, 32000, 48000:
+	default:
+		return nil, fmt.Errorf("vad: webrtc backend only supports 8000/16000/32000/48000Hz, got %dHz", sampleRate)
+	}
+
+	inst := C.WebRtcVad_Create()
+	if inst == nil {
+		return nil, fmt.Errorf("vad: WebRtcVad_Create failed")
+	}
+	if C.WebRtcVad_Init(inst) != 0 {
+		return nil, fmt.Errorf("vad: WebRtcVad_Init failed")
+	}
+	// Aggressiveness mode 2 (of 0-3): moderately aggressive about filtering
+	// out non-speech.
+	if C.WebRtcVad_set_mode(inst, 2) != 0 {
+		return nil, fmt.Errorf("vad: WebRtcVad_set_mode failed")
+	}
+	return &webrtcDetector{inst: inst, sampleRate: sampleRate}, nil
+}
+
+// Detect reports whether frame (10, 20, or 30ms of mono audio, as required
+// by WebRtcVad_Process) contains speech.
+func (d *webrtcDetector) Detect(frame []float32) bool {
+	if len(frame) == 0 {
+		return false
+	}
+
+	samples := make([]C.int16_t, len(frame))
+	for i, s := range frame {
+		samples[i] = C.int16_t(s * 32767)
+	}
+
+	result := C.WebRtcVad_Process(d.inst, C.int(d.sampleRate), &samples[0], C.size_t(len(samples)))
+	return result == 1
+}
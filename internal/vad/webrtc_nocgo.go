@@ -0,0 +1,10 @@
+//go:build !cgo
+// +build !cgo
+
+package vad
+
+import "fmt"
+
+func newWebRTCDetector(sampleRate int) (Detector, error) {
+	return nil, fmt.Errorf("vad: webrtc backend requires building with cgo enabled")
+}
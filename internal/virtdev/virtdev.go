@@ -0,0 +1,31 @@
+// Package virtdev manages the platform-specific virtual audio loopback
+// device that system-jammer mode needs to capture and re-emit system audio:
+// BlackHole on macOS, a PulseAudio/PipeWire null-sink on Linux, and VB-Audio
+// Virtual Cable on Windows.
+package virtdev
+
+// VirtualDevice sets up and tears down the virtual loopback device used by
+// system-jammer mode. New returns the implementation for the current
+// platform, selected at build time via build tags.
+type VirtualDevice interface {
+	// Ensure makes sure the virtual device exists, installing or loading it
+	// if necessary. It is safe to call repeatedly.
+	Ensure() error
+
+	// InputDeviceName is the PortAudio device name system-jammer mode should
+	// capture system audio from.
+	InputDeviceName() string
+
+	// OutputDeviceName is the PortAudio device name users should set as
+	// their system's audio output so applications route through it.
+	OutputDeviceName() string
+
+	// Teardown removes any resources Ensure created (unloads modules,
+	// uninstalls drivers, etc).
+	Teardown() error
+}
+
+// New returns the VirtualDevice implementation for the current platform.
+func New() VirtualDevice {
+	return newPlatformDevice()
+}
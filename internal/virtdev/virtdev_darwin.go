@@ -1,7 +1,7 @@
 //go:build darwin
 // +build darwin
 
-package main
+package virtdev
 
 import (
 	"fmt"
@@ -12,12 +12,48 @@ import (
 )
 
 const blackHoleDeviceName = "BlackHole 2ch"
+const blackHoleDriverPath = "/Library/Audio/Plug-Ins/HAL/BlackHole.driver"
+
+func newPlatformDevice() VirtualDevice {
+	return &blackHoleDevice{}
+}
+
+// blackHoleDevice manages the BlackHole virtual audio driver on macOS.
+type blackHoleDevice struct{}
+
+func (d *blackHoleDevice) InputDeviceName() string  { return blackHoleDeviceName }
+func (d *blackHoleDevice) OutputDeviceName() string { return blackHoleDeviceName }
+
+// Ensure installs the BlackHole driver if it isn't already present.
+func (d *blackHoleDevice) Ensure() error {
+	if checkForBlackHole() {
+		return nil
+	}
+	fmt.Println("BlackHole not found, attempting installation...")
+	return installBlackHole()
+}
+
+// Teardown removes the BlackHole driver via pkgutil and deletes its bundle.
+func (d *blackHoleDevice) Teardown() error {
+	if !checkForBlackHole() {
+		return nil
+	}
+
+	if out, err := exec.Command("sudo", "pkgutil", "--forget", "audio.existential.BlackHole2ch").CombinedOutput(); err != nil {
+		return fmt.Errorf("pkgutil --forget failed: %w (%s)", err, out)
+	}
+
+	if err := os.RemoveAll(blackHoleDriverPath); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", blackHoleDriverPath, err)
+	}
+
+	return nil
+}
 
 // checkForBlackHole checks if the BlackHole audio device is installed.
 func checkForBlackHole() bool {
 	// We can check for the presence of the BlackHole driver directory.
 	// This is a reliable way to see if it's installed without parsing command output.
-	const blackHoleDriverPath = "/Library/Audio/Plug-Ins/HAL/BlackHole.driver"
 	if _, err := os.Stat(blackHoleDriverPath); err == nil {
 		fmt.Println("BlackHole audio driver is already installed.")
 		return true
@@ -69,4 +105,4 @@ func installBlackHole() error {
 	// 3. Clean up
 	fmt.Println("Installation successful. Cleaning up...")
 	return os.Remove(pkgPath)
-}
\ No newline at end of file
+}
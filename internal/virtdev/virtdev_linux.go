@@ -0,0 +1,118 @@
+//go:build linux
+// +build linux
+
+package virtdev
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+const (
+	nullSinkName  = "camouflage_jammer_sink"
+	monitorSource = nullSinkName + ".monitor"
+)
+
+func newPlatformDevice() VirtualDevice {
+	return &pulseDevice{}
+}
+
+// pulseDevice manages a PulseAudio/PipeWire null-sink, loaded dynamically
+// over the native PA protocol the same way NoiseTorch creates its virtual
+// source.
+type pulseDevice struct {
+	sinkModuleID string
+}
+
+func (d *pulseDevice) InputDeviceName() string  { return monitorSource }
+func (d *pulseDevice) OutputDeviceName() string { return nullSinkName }
+
+// Ensure loads a module-null-sink, the virtual device other apps render to
+// and camouflage captures from (via its monitor), remembering its module ID
+// so Teardown can unload it cleanly. There is no loopback to the real
+// output device here: runSystemJammer re-emits the processed audio to the
+// real output itself, so a loopback routing the sink back out would double
+// it up, and a loopback routing the default source in (the obvious way to
+// get "sink=" args wrong) would contaminate the captured system audio with
+// the real microphone instead.
+func (d *pulseDevice) Ensure() error {
+	if d.sinkModuleID != "" {
+		return nil
+	}
+
+	sinkID, err := loadModule("module-null-sink",
+		"sink_name="+nullSinkName,
+		`sink_properties=device.description="Camouflage-Jammer"`)
+	if err != nil {
+		return fmt.Errorf("failed to load module-null-sink: %w", err)
+	}
+	d.sinkModuleID = sinkID
+
+	return nil
+}
+
+// Teardown unloads the null-sink module. If this pulseDevice never called
+// Ensure itself (e.g. `camouflage uninstall` running as a fresh process
+// after the session that loaded the module has already exited), there is no
+// module ID in memory to unload, so the module is looked up by name instead.
+func (d *pulseDevice) Teardown() error {
+	id := d.sinkModuleID
+	if id == "" {
+		found, err := findModuleByArg("module-null-sink", "sink_name="+nullSinkName)
+		if err != nil {
+			return fmt.Errorf("teardown errors: %s", err.Error())
+		}
+		id = found
+	}
+	if id == "" {
+		return nil
+	}
+
+	if err := unloadModule(id); err != nil {
+		return fmt.Errorf("teardown errors: %s", err.Error())
+	}
+	d.sinkModuleID = ""
+	return nil
+}
+
+// loadModule runs `pactl load-module` and returns the numeric module ID
+// PulseAudio/PipeWire assigns it, so it can be unloaded later.
+func loadModule(name string, args ...string) (string, error) {
+	cmdArgs := append([]string{"load-module", name}, args...)
+	out, err := exec.Command("pactl", cmdArgs...).Output()
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// unloadModule runs `pactl unload-module <id>`.
+func unloadModule(id string) error {
+	return exec.Command("pactl", "unload-module", id).Run()
+}
+
+// findModuleByArg looks up an already-loaded module by name and a
+// substring its load arguments must contain, via `pactl list short
+// modules`. It returns "" if no matching module is currently loaded.
+// PulseAudio/PipeWire module IDs only exist in the memory of the process
+// that loaded them, but the modules themselves persist independently of
+// that process, so this is how Teardown recovers them when it runs in a
+// different process than the one that called Ensure.
+func findModuleByArg(name, argSubstring string) (string, error) {
+	out, err := exec.Command("pactl", "list", "short", "modules").Output()
+	if err != nil {
+		return "", err
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) < 3 || fields[1] != name {
+			continue
+		}
+		if strings.Contains(fields[2], argSubstring) {
+			return fields[0], nil
+		}
+	}
+	return "", nil
+}
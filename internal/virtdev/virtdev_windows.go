@@ -0,0 +1,54 @@
+//go:build windows
+// +build windows
+
+package virtdev
+
+import (
+	"fmt"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// vbCableDeviceName is the PortAudio device name exposed by VB-Audio Virtual
+// Cable once installed.
+const vbCableDeviceName = "CABLE Output"
+
+func newPlatformDevice() VirtualDevice {
+	return &vbCableDevice{}
+}
+
+// vbCableDevice detects VB-Audio Virtual Cable. Unlike BlackHole, VB-Cable
+// has no silent/scriptable installer, so Ensure can only detect it and point
+// the user at the manual installer.
+type vbCableDevice struct{}
+
+func (d *vbCableDevice) InputDeviceName() string  { return vbCableDeviceName }
+func (d *vbCableDevice) OutputDeviceName() string { return "CABLE Input" }
+
+// Ensure checks whether VB-Audio Virtual Cable is installed by looking for
+// its PortAudio device. It does not attempt to install it.
+func (d *vbCableDevice) Ensure() error {
+	if portAudioHasDevice(vbCableDeviceName) {
+		return nil
+	}
+	return fmt.Errorf("VB-Audio Virtual Cable not found; download and install it from https://vb-audio.com/Cable/ and re-run camouflage")
+}
+
+// Teardown is a no-op: VB-Audio Virtual Cable is a manually installed
+// driver, not something camouflage loads or unloads per run.
+func (d *vbCableDevice) Teardown() error {
+	return nil
+}
+
+func portAudioHasDevice(name string) bool {
+	devices, err := portaudio.Devices()
+	if err != nil {
+		return false
+	}
+	for _, dev := range devices {
+		if dev.Name == name {
+			return true
+		}
+	}
+	return false
+}